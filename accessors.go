@@ -0,0 +1,185 @@
+package gdstore
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// converter is a Codec for values that can always be encoded, such as numbers, which lets the store encode them
+// without having to handle an error that can never happen
+type converter[T any] struct {
+	format func(value T) []byte
+	parse  func(data []byte) (T, error)
+}
+
+func (c converter[T]) Encode(value T) ([]byte, error) {
+	return c.format(value), nil
+}
+
+func (c converter[T]) Decode(data []byte) (T, error) {
+	return c.parse(data)
+}
+
+var (
+	intConverter = converter[int]{
+		format: func(value int) []byte { return []byte(strconv.Itoa(value)) },
+		parse:  func(data []byte) (int, error) { return strconv.Atoi(string(data)) },
+	}
+	int64Converter = converter[int64]{
+		format: func(value int64) []byte { return []byte(strconv.FormatInt(value, 10)) },
+		parse:  func(data []byte) (int64, error) { return strconv.ParseInt(string(data), 10, 64) },
+	}
+	uint64Converter = converter[uint64]{
+		format: func(value uint64) []byte { return []byte(strconv.FormatUint(value, 10)) },
+		parse:  func(data []byte) (uint64, error) { return strconv.ParseUint(string(data), 10, 64) },
+	}
+	float64Converter = converter[float64]{
+		format: func(value float64) []byte { return []byte(strconv.FormatFloat(value, 'f', -1, 64)) },
+		parse:  func(data []byte) (float64, error) { return strconv.ParseFloat(string(data), 64) },
+	}
+	boolConverter = converter[bool]{
+		format: func(value bool) []byte { return []byte(strconv.FormatBool(value)) },
+		parse:  func(data []byte) (bool, error) { return strconv.ParseBool(string(data)) },
+	}
+	timeConverter = converter[time.Time]{
+		format: func(value time.Time) []byte { return []byte(value.Format(time.RFC3339Nano)) },
+		parse:  func(data []byte) (time.Time, error) { return time.Parse(time.RFC3339Nano, string(data)) },
+	}
+	durationConverter = converter[time.Duration]{
+		format: func(value time.Duration) []byte { return []byte(value.String()) },
+		parse:  func(data []byte) (time.Duration, error) { return time.ParseDuration(string(data)) },
+	}
+)
+
+// GetStringOr does the same thing as GetString, but returns defaultValue if the key doesn't exist
+func (store *GDStore) GetStringOr(key string, defaultValue string) string {
+	return getDecodedOr(store, key, StringCodec{}.Decode, defaultValue)
+}
+
+// GetIntOr does the same thing as GetInt, but returns defaultValue if the key doesn't exist or its value
+// is not an int
+func (store *GDStore) GetIntOr(key string, defaultValue int) int {
+	return getDecodedOr(store, key, intConverter.Decode, defaultValue)
+}
+
+// GetInt64 does the same thing as Get, but converts the value to an int64
+func (store *GDStore) GetInt64(key string) (int64, bool, error) {
+	return getDecoded(store, key, int64Converter.Decode)
+}
+
+// GetInt64Or does the same thing as GetInt64, but returns defaultValue if the key doesn't exist or its value
+// is not an int64
+func (store *GDStore) GetInt64Or(key string, defaultValue int64) int64 {
+	return getDecodedOr(store, key, int64Converter.Decode, defaultValue)
+}
+
+// GetUint64 does the same thing as Get, but converts the value to an uint64
+func (store *GDStore) GetUint64(key string) (uint64, bool, error) {
+	return getDecoded(store, key, uint64Converter.Decode)
+}
+
+// GetUint64Or does the same thing as GetUint64, but returns defaultValue if the key doesn't exist or its value
+// is not an uint64
+func (store *GDStore) GetUint64Or(key string, defaultValue uint64) uint64 {
+	return getDecodedOr(store, key, uint64Converter.Decode, defaultValue)
+}
+
+// GetFloat64 does the same thing as Get, but converts the value to a float64
+func (store *GDStore) GetFloat64(key string) (float64, bool, error) {
+	return getDecoded(store, key, float64Converter.Decode)
+}
+
+// GetFloat64Or does the same thing as GetFloat64, but returns defaultValue if the key doesn't exist or its value
+// is not a float64
+func (store *GDStore) GetFloat64Or(key string, defaultValue float64) float64 {
+	return getDecodedOr(store, key, float64Converter.Decode, defaultValue)
+}
+
+// GetBool does the same thing as Get, but converts the value to a bool.
+// Accepted values are the same as strconv.ParseBool (e.g. true, false, 1, 0)
+func (store *GDStore) GetBool(key string) (bool, bool, error) {
+	return getDecoded(store, key, boolConverter.Decode)
+}
+
+// GetBoolOr does the same thing as GetBool, but returns defaultValue if the key doesn't exist or its value
+// is not a bool
+func (store *GDStore) GetBoolOr(key string, defaultValue bool) bool {
+	return getDecodedOr(store, key, boolConverter.Decode, defaultValue)
+}
+
+// GetTime does the same thing as Get, but converts the value, which must be formatted as RFC3339, to a time.Time
+func (store *GDStore) GetTime(key string) (time.Time, bool, error) {
+	return getDecoded(store, key, timeConverter.Decode)
+}
+
+// GetTimeOr does the same thing as GetTime, but returns defaultValue if the key doesn't exist or its value
+// is not a time
+func (store *GDStore) GetTimeOr(key string, defaultValue time.Time) time.Time {
+	return getDecodedOr(store, key, timeConverter.Decode, defaultValue)
+}
+
+// GetDuration does the same thing as Get, but converts the value (e.g. 1h30m) to a time.Duration
+func (store *GDStore) GetDuration(key string) (time.Duration, bool, error) {
+	return getDecoded(store, key, durationConverter.Decode)
+}
+
+// GetDurationOr does the same thing as GetDuration, but returns defaultValue if the key doesn't exist or its value
+// is not a duration
+func (store *GDStore) GetDurationOr(key string, defaultValue time.Duration) time.Duration {
+	return getDecodedOr(store, key, durationConverter.Decode, defaultValue)
+}
+
+// GetJSON does the same thing as Get, but unmarshals the value into v.
+// If the key doesn't exist, v is left untouched.
+func (store *GDStore) GetJSON(key string, v interface{}) (ok bool, err error) {
+	_, ok, err = getDecoded(store, key, func(data []byte) (interface{}, error) {
+		return v, json.Unmarshal(data, v)
+	})
+	return
+}
+
+// PutString does the same thing as Put, but takes a string as value
+func (store *GDStore) PutString(key string, value string) error {
+	return putEncoded(store, key, value, StringCodec{}.Encode)
+}
+
+// PutInt does the same thing as Put, but takes an int as value
+func (store *GDStore) PutInt(key string, value int) error {
+	return putEncoded(store, key, value, intConverter.Encode)
+}
+
+// PutInt64 does the same thing as Put, but takes an int64 as value
+func (store *GDStore) PutInt64(key string, value int64) error {
+	return putEncoded(store, key, value, int64Converter.Encode)
+}
+
+// PutUint64 does the same thing as Put, but takes an uint64 as value
+func (store *GDStore) PutUint64(key string, value uint64) error {
+	return putEncoded(store, key, value, uint64Converter.Encode)
+}
+
+// PutFloat64 does the same thing as Put, but takes a float64 as value
+func (store *GDStore) PutFloat64(key string, value float64) error {
+	return putEncoded(store, key, value, float64Converter.Encode)
+}
+
+// PutBool does the same thing as Put, but takes a bool as value
+func (store *GDStore) PutBool(key string, value bool) error {
+	return putEncoded(store, key, value, boolConverter.Encode)
+}
+
+// PutTime does the same thing as Put, but takes a time.Time as value, which is persisted as RFC3339
+func (store *GDStore) PutTime(key string, value time.Time) error {
+	return putEncoded(store, key, value, timeConverter.Encode)
+}
+
+// PutDuration does the same thing as Put, but takes a time.Duration as value
+func (store *GDStore) PutDuration(key string, value time.Duration) error {
+	return putEncoded(store, key, value, durationConverter.Encode)
+}
+
+// PutJSON does the same thing as Put, but marshals v to JSON
+func (store *GDStore) PutJSON(key string, v interface{}) error {
+	return putEncoded(store, key, v, JSONCodec[interface{}]{}.Encode)
+}
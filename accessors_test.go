@@ -0,0 +1,113 @@
+package gdstore
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGDStore_TypedAccessors(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	now := time.Now()
+	_ = store.PutString("string", "john")
+	_ = store.PutInt("int", -42)
+	_ = store.PutInt64("int64", -1<<40)
+	_ = store.PutUint64("uint64", 1<<63)
+	_ = store.PutFloat64("float64", 3.14)
+	_ = store.PutBool("bool", true)
+	_ = store.PutTime("time", now)
+	_ = store.PutDuration("duration", 90*time.Minute)
+	checkValueForKey(t, store, "uint64", []byte("9223372036854775808"))
+	checkValueForKey(t, store, "duration", []byte("1h30m0s"))
+	if value, ok := store.GetString("string"); !ok || value != "john" {
+		t.Errorf("[%s] Expected john, got %s", t.Name(), value)
+	}
+	if value, ok, err := store.GetInt("int"); !ok || err != nil || value != -42 {
+		t.Errorf("[%s] Expected -42, got %d", t.Name(), value)
+	}
+	if value, ok, err := store.GetInt64("int64"); !ok || err != nil || value != -1<<40 {
+		t.Errorf("[%s] Expected %d, got %d", t.Name(), int64(-1<<40), value)
+	}
+	if value, ok, err := store.GetUint64("uint64"); !ok || err != nil || value != 1<<63 {
+		t.Errorf("[%s] Expected %d, got %d", t.Name(), uint64(1<<63), value)
+	}
+	if value, ok, err := store.GetFloat64("float64"); !ok || err != nil || value != 3.14 {
+		t.Errorf("[%s] Expected 3.14, got %f", t.Name(), value)
+	}
+	if value, ok, err := store.GetBool("bool"); !ok || err != nil || !value {
+		t.Errorf("[%s] Expected true, got %v", t.Name(), value)
+	}
+	if value, ok, err := store.GetTime("time"); !ok || err != nil || !value.Equal(now) {
+		t.Errorf("[%s] Expected %s, got %s", t.Name(), now, value)
+	}
+	if value, ok, err := store.GetDuration("duration"); !ok || err != nil || value != 90*time.Minute {
+		t.Errorf("[%s] Expected 1h30m, got %s", t.Name(), value)
+	}
+	store.Close()
+}
+
+func TestGDStore_TypedAccessorsWithInvalidValue(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutString("test", "not-a-number")
+	var decodeError *DecodeError
+	if _, ok, err := store.GetInt64("test"); !ok || !errors.As(err, &decodeError) || decodeError.Key != "test" {
+		t.Errorf("[%s] Expected a DecodeError for key test, got %v instead", t.Name(), err)
+	}
+	if _, _, err := store.GetBool("test"); err == nil {
+		t.Errorf("[%s] Expected an error", t.Name())
+	}
+	if _, _, err := store.GetTime("test"); err == nil {
+		t.Errorf("[%s] Expected an error", t.Name())
+	}
+	if _, ok, err := store.GetFloat64("does-not-exist"); ok || err != nil {
+		t.Errorf("[%s] Expected key to not exist without returning an error", t.Name())
+	}
+	store.Close()
+}
+
+func TestGDStore_TypedAccessorsOr(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutInt("int", 42)
+	_ = store.PutString("invalid", "invalid")
+	if value := store.GetIntOr("int", 1); value != 42 {
+		t.Errorf("[%s] Expected 42, got %d", t.Name(), value)
+	}
+	if value := store.GetIntOr("does-not-exist", 1); value != 1 {
+		t.Errorf("[%s] Expected the default value, got %d", t.Name(), value)
+	}
+	if value := store.GetIntOr("invalid", 1); value != 1 {
+		t.Errorf("[%s] Expected the default value, got %d", t.Name(), value)
+	}
+	if value := store.GetStringOr("does-not-exist", "default"); value != "default" {
+		t.Errorf("[%s] Expected the default value, got %s", t.Name(), value)
+	}
+	if value := store.GetDurationOr("invalid", time.Second); value != time.Second {
+		t.Errorf("[%s] Expected the default value, got %s", t.Name(), value)
+	}
+	if value := store.GetBoolOr("does-not-exist", true); !value {
+		t.Errorf("[%s] Expected the default value, got %v", t.Name(), value)
+	}
+	store.Close()
+}
+
+func TestGDStore_JSON(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	if err := store.PutJSON("john", testUser{Name: "John", Age: 42}); err != nil {
+		t.Fatalf("[%s] Unexpected error: %s", t.Name(), err.Error())
+	}
+	checkValueForKey(t, store, "john", []byte(`{"Name":"John","Age":42}`))
+	var user testUser
+	if ok, err := store.GetJSON("john", &user); !ok || err != nil || user.Name != "John" || user.Age != 42 {
+		t.Errorf("[%s] Expected John (42), got %+v", t.Name(), user)
+	}
+	_ = store.PutString("invalid", "{")
+	var decodeError *DecodeError
+	if _, err := store.GetJSON("invalid", &user); !errors.As(err, &decodeError) {
+		t.Errorf("[%s] Expected a DecodeError, got %v instead", t.Name(), err)
+	}
+	store.Close()
+}
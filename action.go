@@ -5,4 +5,30 @@ type Action string
 var (
 	ActionPut    Action = "SET"
 	ActionDelete Action = "DEL"
+	ActionExpire Action = "EXP"
+	ActionClear  Action = "CLR"
+
+	// ActionRename moves the value (and expiration) of the entry's key to the key stored in the entry's value
+	ActionRename Action = "REN"
+
+	// ActionMerge combines the value of the entry's key with the entry's value using the merge operator
+	// stored in the entry's attributes (see RegisterMergeOperator)
+	ActionMerge Action = "MRG"
+
+	// ActionHash replaces every field of the hash stored in the entry's bucket by the fields encoded in the
+	// entry's value. It is used to persist an entire hash as a single entry when the store is consolidated.
+	ActionHash Action = "HSH"
+
+	// ActionSequence is written when the store is consolidated. Its sequence number is the last sequence number
+	// that was assigned, and its value is the sequence number below which changes may have been purged
+	// (see ChangesSince).
+	ActionSequence Action = "SEQ"
+
+	// ActionRetention is written when the tombstone retention is changed with WithTombstoneRetention, as well as when
+	// the store is consolidated if the change feed is enabled. Its value is the retention in nanoseconds.
+	ActionRetention Action = "RET"
+
+	// ActionBatch precedes a batch of entries, and its value is the number of entries in the batch.
+	// The entries of a batch are only applied if all of them have been persisted.
+	ActionBatch Action = "BAT"
 )
@@ -0,0 +1,226 @@
+package gdstore
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+const (
+	// internalBucketPrefix is the prefix of the buckets used to persist the store's own data structures (e.g. queues),
+	// which cannot be used as the name of a Bucket
+	internalBucketPrefix = "\x00"
+)
+
+var (
+	ErrInvalidBucketName = errors.New("bucket name must not be empty or start with a null character")
+)
+
+// Bucket is a namespace within a store.
+//
+// Entries in a bucket are persisted in the same file as the store, but they're isolated from the store's own
+// entries and from the entries of other buckets: they're not returned by the store's Keys or Count, and two buckets
+// can have the same key without conflicting. The name of the bucket is persisted alongside each entry rather than
+// being added to the key.
+//
+// Buckets only support a subset of the store's features; expiration, eviction and indexes only apply to the
+// store's own entries.
+type Bucket struct {
+	store *GDStore
+	name  string
+}
+
+// Bucket returns the bucket with the given name. Buckets don't need to be created before being used.
+//
+// The name must not be empty or start with a null character, which is reserved for the buckets used internally by
+// the store, otherwise, all writes will return ErrInvalidBucketName, and the bucket will always appear empty.
+func (store *GDStore) Bucket(name string) *Bucket {
+	return &Bucket{store: store, name: name}
+}
+
+// Buckets returns the names of all buckets that contain at least one entry, in lexicographic order
+func (store *GDStore) Buckets() []string {
+	store.mux.RLock()
+	names := make([]string, 0, len(store.buckets))
+	for name := range store.buckets {
+		if isInternalBucket(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	store.mux.RUnlock()
+	sort.Strings(names)
+	return names
+}
+
+// Name returns the name of the bucket
+func (bucket *Bucket) Name() string {
+	return bucket.name
+}
+
+// Get returns the value of a key in the bucket as well as a bool that indicates whether an entry exists for that key
+func (bucket *Bucket) Get(key string) (value []byte, ok bool) {
+	if !bucket.isValid() {
+		return nil, false
+	}
+	bucket.store.mux.RLock()
+	value, ok = bucket.store.buckets[bucket.name][key]
+	bucket.store.mux.RUnlock()
+	return
+}
+
+// Put creates an entry or updates the value of an existing key in the bucket
+func (bucket *Bucket) Put(key string, value []byte) error {
+	if !bucket.isValid() {
+		return ErrInvalidBucketName
+	}
+	unlock := bucket.store.lockKeys(bucket.lockKey(key))
+	defer unlock()
+	bucket.store.mux.Lock()
+	defer bucket.store.mux.Unlock()
+	bucket.store.setInBucket(bucket.name, key, value)
+	return bucket.store.appendEntryToFile(bucket.newEntry(ActionPut, key, value))
+}
+
+// Delete removes a key from the bucket
+func (bucket *Bucket) Delete(key string) error {
+	if !bucket.isValid() {
+		return ErrInvalidBucketName
+	}
+	unlock := bucket.store.lockKeys(bucket.lockKey(key))
+	defer unlock()
+	bucket.store.mux.Lock()
+	defer bucket.store.mux.Unlock()
+	bucket.store.removeFromBucket(bucket.name, key)
+	return bucket.store.appendEntryToFile(bucket.newEntry(ActionDelete, key, nil))
+}
+
+// Keys returns a list of all keys in the bucket
+func (bucket *Bucket) Keys() []string {
+	if !bucket.isValid() {
+		return []string{}
+	}
+	bucket.store.mux.RLock()
+	defer bucket.store.mux.RUnlock()
+	keys := make([]string, 0, len(bucket.store.buckets[bucket.name]))
+	for key := range bucket.store.buckets[bucket.name] {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Count returns the total number of entries in the bucket
+func (bucket *Bucket) Count() int {
+	if !bucket.isValid() {
+		return 0
+	}
+	bucket.store.mux.RLock()
+	defer bucket.store.mux.RUnlock()
+	return len(bucket.store.buckets[bucket.name])
+}
+
+// Drop removes every entry in the bucket. Rather than persisting a DEL for each key, a single CLR is persisted.
+func (bucket *Bucket) Drop() error {
+	if !bucket.isValid() {
+		return ErrInvalidBucketName
+	}
+	unlock := bucket.store.lockAllKeys()
+	defer unlock()
+	bucket.store.mux.Lock()
+	defer bucket.store.mux.Unlock()
+	bucket.store.removeBucket(bucket.name)
+	return bucket.store.appendEntryToFile(bucket.newEntry(ActionClear, "", nil))
+}
+
+// isValid returns whether the name of the bucket is neither empty nor reserved for the buckets used internally
+func (bucket *Bucket) isValid() bool {
+	return bucket.name != "" && !isInternalBucket(bucket.name)
+}
+
+// isInternalBucket returns whether a bucket is used internally by the store rather than through Bucket
+func isInternalBucket(name string) bool {
+	return strings.HasPrefix(name, internalBucketPrefix)
+}
+
+// newEntry creates a new entry that belongs to the bucket
+func (bucket *Bucket) newEntry(action Action, key string, value []byte) *Entry {
+	entry := newEntry(action, key, value)
+	entry.Bucket = bucket.name
+	return entry
+}
+
+// lockKey returns the key used to lock a key of the bucket, so that it doesn't share its lock with the
+// store's key with the same name
+func (bucket *Bucket) lockKey(key string) string {
+	return bucket.name + "\x00" + key
+}
+
+// setInBucket creates or updates an entry in a bucket in memory. Must be called while holding store.mux
+func (store *GDStore) setInBucket(bucket, key string, value []byte) {
+	if store.buckets[bucket] == nil {
+		store.buckets[bucket] = make(map[string][]byte)
+	}
+	store.buckets[bucket][key] = value
+	store.indexSortedSetMember(bucket, key, value)
+	store.indexQueueKey(bucket, key, value)
+}
+
+// removeFromBucket removes an entry from a bucket in memory. Must be called while holding store.mux
+func (store *GDStore) removeFromBucket(bucket, key string) {
+	delete(store.buckets[bucket], key)
+	store.unindexSortedSetMember(bucket, key)
+	store.unindexQueueKey(bucket, key)
+	if len(store.buckets[bucket]) == 0 {
+		delete(store.buckets, bucket)
+	}
+}
+
+// removeBucket removes every entry of a bucket from memory. Must be called while holding store.mux
+func (store *GDStore) removeBucket(bucket string) {
+	delete(store.buckets, bucket)
+	delete(store.sortedSets, bucket)
+	delete(store.queues, bucket)
+}
+
+// applyBucketEntry applies an entry that belongs to a bucket read from the store's file to memory
+func (store *GDStore) applyBucketEntry(entry *Entry) {
+	switch entry.Action {
+	case ActionPut:
+		store.setInBucket(entry.Bucket, entry.Key, entry.Value)
+	case ActionDelete:
+		store.removeFromBucket(entry.Bucket, entry.Key)
+	case ActionClear:
+		store.removeBucket(entry.Bucket)
+	case ActionHash:
+		fields, err := decodeHash(entry.Value)
+		if err != nil {
+			// Like any other entry that cannot be decoded, the entry is skipped
+			return
+		}
+		store.removeBucket(entry.Bucket)
+		for field, value := range fields {
+			store.setInBucket(entry.Bucket, field, value)
+		}
+	}
+}
+
+// bucketSnapshotEntries returns the entries required to re-create the current state of every bucket.
+// Must be called while holding store.mux
+func (store *GDStore) bucketSnapshotEntries() []*Entry {
+	var entries []*Entry
+	for name, bucket := range store.buckets {
+		if isHashBucket(name) {
+			// All fields of a hash are combined into a single entry
+			entry := newEntry(ActionHash, "", encodeHash(bucket))
+			entry.Bucket = name
+			entries = append(entries, entry)
+			continue
+		}
+		for key, value := range bucket {
+			entry := newEntry(ActionPut, key, value)
+			entry.Bucket = name
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
@@ -0,0 +1,107 @@
+package gdstore
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBucket(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	users := store.Bucket("users")
+	groups := store.Bucket("groups")
+	_ = store.Put("1", []byte("root"))
+	_ = users.Put("1", []byte("john"))
+	_ = users.Put("2", []byte("jane"))
+	_ = groups.Put("1", []byte("admins"))
+	checkValueForKey(t, store, "1", []byte("root"))
+	if value, ok := users.Get("1"); !ok || string(value) != "john" {
+		t.Errorf("[%s] Expected key '1' of bucket 'users' to be 'john', got '%s' instead", t.Name(), value)
+	}
+	if value, ok := groups.Get("1"); !ok || string(value) != "admins" {
+		t.Errorf("[%s] Expected key '1' of bucket 'groups' to be 'admins', got '%s' instead", t.Name(), value)
+	}
+	if store.Count() != 1 || len(store.Keys()) != 1 {
+		t.Errorf("[%s] Expected the store to only have 1 entry, got %d instead", t.Name(), store.Count())
+	}
+	if users.Count() != 2 || len(users.Keys()) != 2 {
+		t.Errorf("[%s] Expected bucket 'users' to have 2 entries, got %d instead", t.Name(), users.Count())
+	}
+	_ = users.Delete("2")
+	if names := store.Buckets(); !reflect.DeepEqual(names, []string{"groups", "users"}) {
+		t.Errorf("[%s] Expected buckets [groups users], got %v instead", t.Name(), names)
+	}
+	// The bucket must be persisted as an attribute rather than as part of the key
+	if !strings.Contains(getStoreFileContent(store), "bkt=") {
+		t.Errorf("[%s] Expected the bucket to have been persisted as an attribute", t.Name())
+	}
+	store.Close()
+
+	// Make sure buckets survive reloading the store, which also consolidates the file
+	store = New(TestStoreFile)
+	if value, ok := store.Bucket("users").Get("1"); !ok || string(value) != "john" {
+		t.Errorf("[%s] Expected key '1' of bucket 'users' to be 'john', got '%s' instead", t.Name(), value)
+	}
+	if _, ok := store.Bucket("users").Get("2"); ok {
+		t.Errorf("[%s] Expected key '2' of bucket 'users' to not exist", t.Name())
+	}
+	if store.Count() != 1 || store.Bucket("groups").Count() != 1 {
+		t.Errorf("[%s] Expected the store and bucket 'groups' to have 1 entry each", t.Name())
+	}
+	store.Close()
+}
+
+func TestBucket_Drop(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	users := store.Bucket("users")
+	_ = users.Put("1", nil)
+	_ = users.Put("2", nil)
+	_ = store.Put("1", nil)
+	_ = users.Drop()
+	if users.Count() != 0 || store.Count() != 1 {
+		t.Errorf("[%s] Expected the bucket to be empty and the store to have 1 entry", t.Name())
+	}
+	// 3 SET + 1 CLR
+	if numberOfLines := len(strings.Split(getStoreFileContent(store), "\n")); numberOfLines != 4 {
+		t.Errorf("[%s] Store file should've had 4 lines, but had %d instead", t.Name(), numberOfLines)
+	}
+	store = New(TestStoreFile)
+	if store.Bucket("users").Count() != 0 || store.Count() != 1 {
+		t.Errorf("[%s] Expected the bucket to be empty and the store to have 1 entry after reloading the store", t.Name())
+	}
+	store.Close()
+}
+
+func TestBucket_WithEmptyName(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	if err := store.Bucket("").Put("key", nil); err != ErrInvalidBucketName {
+		t.Errorf("[%s] Expected ErrInvalidBucketName, got %v instead", t.Name(), err)
+	}
+	store.Close()
+}
+
+func TestBucket_WithInternalName(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_, _ = store.Queue("emails").Enqueue([]byte("john@example.com"))
+	_ = store.Bucket("users").Put("1", []byte("john"))
+	if names := store.Buckets(); !reflect.DeepEqual(names, []string{"users"}) {
+		t.Errorf("[%s] Expected buckets [users], got %v instead", t.Name(), names)
+	}
+	internalBucket := store.Bucket(queueBucketPrefix + "emails")
+	if err := internalBucket.Put("seq", nil); err != ErrInvalidBucketName {
+		t.Errorf("[%s] Expected ErrInvalidBucketName, got %v instead", t.Name(), err)
+	}
+	if internalBucket.Count() != 0 {
+		t.Errorf("[%s] Expected the internal bucket to not be accessible through Bucket", t.Name())
+	}
+	// A bucket that merely looks like the one of a queue must not be mistaken for it
+	_ = store.Bucket("queue:emails").Put("job:00000000000000000002", []byte("jane@example.com"))
+	if length := store.Queue("emails").Len(); length != 1 {
+		t.Errorf("[%s] Expected the queue to have 1 job, got %d instead", t.Name(), length)
+	}
+	store.Close()
+}
@@ -0,0 +1,73 @@
+package gdstore
+
+import (
+	"time"
+)
+
+// GetMany returns the values of the keys passed as parameter that exist in the store.
+// Keys that don't exist are omitted from the map.
+//
+// Unlike calling Get for each key, all values are read under a single lock, which means that the result is a
+// consistent view of the store.
+func (store *GDStore) GetMany(keys []string) map[string][]byte {
+	values := make(map[string][]byte, len(keys))
+	store.mux.RLock()
+	now := time.Now().UnixNano()
+	var slidingKeys []string
+	for _, key := range keys {
+		if value, exists := store.data[key]; exists && !store.isExpired(key, now) {
+			values[key] = value
+			if _, sliding := store.slidingTTLs[key]; sliding {
+				slidingKeys = append(slidingKeys, key)
+			}
+		}
+	}
+	tracker := store.evictionTracker
+	store.mux.RUnlock()
+	for _, key := range slidingKeys {
+		store.renew(key)
+	}
+	if tracker != nil {
+		for key := range values {
+			tracker.use(key)
+		}
+	}
+	return values
+}
+
+// DeleteAll removes a list of keys from the store. The deletions are persisted as a single batch.
+func (store *GDStore) DeleteAll(keys []string) error {
+	defer store.runQueuedCallbacks()
+	unlock := store.lockKeys(keys...)
+	defer unlock()
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	var entries []*Entry
+	for _, key := range keys {
+		if _, exists := store.data[key]; exists {
+			store.remove(key)
+			entries = append(entries, newEntry(ActionDelete, key, nil))
+		}
+	}
+	return store.appendBatchToFile(entries)
+}
+
+// Clear removes every entry from the store. Entries in buckets are not affected (see Bucket.Drop).
+//
+// Rather than persisting a DEL for each key, a single CLR is persisted.
+func (store *GDStore) Clear() error {
+	defer store.runQueuedCallbacks()
+	unlock := store.lockAllKeys()
+	defer unlock()
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	store.removeAll()
+	return store.appendEntryToFile(newEntry(ActionClear, "", nil))
+}
+
+// removeAll removes every entry from memory. Must be called while holding store.mux
+func (store *GDStore) removeAll() {
+	for key := range store.data {
+		store.remove(key)
+	}
+}
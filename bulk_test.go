@@ -0,0 +1,58 @@
+package gdstore
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGDStore_GetMany(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutAll(map[string][]byte{"1": []byte("one"), "2": []byte("two")})
+	_ = store.PutWithTTL("3", []byte("three"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	values := store.GetMany([]string{"1", "2", "3", "4"})
+	if len(values) != 2 || string(values["1"]) != "one" || string(values["2"]) != "two" {
+		t.Errorf("[%s] Expected only keys 1 and 2 to be returned, got %v instead", t.Name(), values)
+	}
+	store.Close()
+}
+
+func TestGDStore_DeleteAll(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutAll(map[string][]byte{"1": nil, "2": nil, "3": nil})
+	_ = store.DeleteAll([]string{"1", "2", "4"})
+	if keys := store.Keys(); len(keys) != 1 || keys[0] != "3" {
+		t.Errorf("[%s] Expected only key 3 to be left, got %v instead", t.Name(), keys)
+	}
+	// 3 SET + 1 BAT + 2 DEL
+	if numberOfLines := len(strings.Split(getStoreFileContent(store), "\n")); numberOfLines != 6 {
+		t.Errorf("[%s] Store file should've had 6 lines, but had %d instead", t.Name(), numberOfLines)
+	}
+	store = New(TestStoreFile)
+	checkKeyNotExists(t, store, "1")
+	checkValueForKey(t, store, "3", nil)
+	store.Close()
+}
+
+func TestGDStore_Clear(t *testing.T) {
+	store := New(TestStoreFile).WithOrderedIndex(true)
+	defer deleteTestStoreFile()
+	_ = store.PutAll(map[string][]byte{"1": nil, "2": nil, "3": nil})
+	_ = store.Clear()
+	_ = store.Put("4", nil)
+	if keys := store.Scan("", "", 0); len(keys) != 1 || keys[0] != "4" {
+		t.Errorf("[%s] Expected only key 4 to be left, got %v instead", t.Name(), keys)
+	}
+	// 3 SET + 1 CLR + 1 SET
+	if numberOfLines := len(strings.Split(getStoreFileContent(store), "\n")); numberOfLines != 5 {
+		t.Errorf("[%s] Store file should've had 5 lines, but had %d instead", t.Name(), numberOfLines)
+	}
+	store = New(TestStoreFile)
+	if store.Count() != 1 {
+		t.Errorf("[%s] Expected to have 1 entry after reloading the store, got %d instead", t.Name(), store.Count())
+	}
+	store.Close()
+}
@@ -0,0 +1,215 @@
+package gdstore
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+var (
+	ErrChangesTruncated   = errors.New("changes since the given sequence number have been purged")
+	ErrChangeFeedDisabled = errors.New("change feed is disabled, see WithTombstoneRetention")
+)
+
+// Change is the latest change made to a key, as returned by ChangesSince
+type Change struct {
+	// Sequence is the sequence number of the entry that made the change
+	Sequence uint64
+
+	// Action is ActionPut if the key exists, and ActionDelete if it was removed
+	Action Action
+
+	Key string
+
+	// Value is the current value of the key, or nil if the key was removed
+	Value []byte
+
+	// Expiry is the time at which the key expires, in nanoseconds since the Unix epoch. 0 means no expiration.
+	Expiry int64
+}
+
+// tombstone is a key that has been removed
+type tombstone struct {
+	sequence  uint64
+	timestamp int64
+}
+
+// WithTombstoneRetention enables the change feed (see ChangesSince) and sets the duration for which removed keys are
+// kept so that they can be returned by ChangesSince. Tombstones that are older than this duration are purged when the
+// store is consolidated.
+//
+// The retention is persisted in the store's file, so it also applies to the consolidation performed by New the next
+// time the store is loaded. Changes made before the change feed was enabled are only returned to consumers starting
+// from sequence number 0. Setting the retention to 0 disables the change feed.
+//
+// The default value for the retention is 0, which means that the change feed is disabled
+func (store *GDStore) WithTombstoneRetention(retention time.Duration) *GDStore {
+	if retention < 0 {
+		retention = 0
+	}
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	if retention == store.tombstoneRetention {
+		return store
+	}
+	entries := []*Entry{newEntry(ActionRetention, "", int64Converter.format(int64(retention)))}
+	if store.tombstoneRetention == 0 && store.sequence > 0 {
+		// Keys removed while the change feed was disabled have no tombstone, so consumers that have seen some, but
+		// not all, of the previous changes must start over
+		store.sequenceWatermark = store.sequence
+		header := newEntry(ActionSequence, "", uint64Converter.format(store.sequenceWatermark))
+		header.Sequence = store.sequence
+		entries = append(entries, header)
+	} else if retention == 0 {
+		store.tombstones = make(map[string]tombstone)
+	}
+	store.tombstoneRetention = retention
+	if err := store.appendBatchToFile(entries); err != nil {
+		panic(err)
+	}
+	return store
+}
+
+// isChangeFeedEnabled returns whether WithTombstoneRetention has been used to enable the change feed.
+// Must be called while holding store.mux
+func (store *GDStore) isChangeFeedEnabled() bool {
+	return store.tombstoneRetention > 0
+}
+
+// Sequence returns the sequence number of the last entry written to the store.
+//
+// Every entry written to the store is assigned a sequence number greater than that of the entries written before it.
+// Sequence numbers are persisted, but they're only guaranteed to keep increasing across restarts if the change feed
+// is enabled (see WithTombstoneRetention).
+func (store *GDStore) Sequence() uint64 {
+	store.mux.RLock()
+	defer store.mux.RUnlock()
+	return store.sequence
+}
+
+// ChangesSince returns the latest change made to every key that changed after the given sequence number, ordered by
+// sequence number, then by key. Changes made to entries in buckets are not included.
+//
+// A consumer that keeps track of the highest sequence number it has seen can resume from where it left off, even
+// after a restart: changes are compacted, so a key that was updated several times is only returned once, with its
+// current value, and a key that was removed is returned with ActionDelete for as long as it's retained (see
+// WithTombstoneRetention). Keys that expire are not returned as removed, so the consumer should rely on Expiry.
+//
+// If changes made after the given sequence number have been purged, ErrChangesTruncated is returned, in which case
+// the consumer should start over from sequence number 0, which returns every key that currently exists.
+//
+// The change feed must be enabled with WithTombstoneRetention, otherwise, ErrChangeFeedDisabled is returned.
+func (store *GDStore) ChangesSince(sequence uint64) ([]Change, error) {
+	store.mux.RLock()
+	defer store.mux.RUnlock()
+	if !store.isChangeFeedEnabled() {
+		return nil, ErrChangeFeedDisabled
+	}
+	if sequence > 0 && sequence < store.sequenceWatermark {
+		return nil, ErrChangesTruncated
+	}
+	now := time.Now().UnixNano()
+	var changes []Change
+	for key, keySequence := range store.keySequences {
+		// Keys that have expired may have already been removed (e.g. by the janitor), in which case their sequence
+		// number is only purged on the next consolidation
+		if _, exists := store.data[key]; keySequence > sequence && exists && !store.isExpired(key, now) {
+			changes = append(changes, Change{Sequence: keySequence, Action: ActionPut, Key: key, Value: store.data[key], Expiry: store.expiries[key]})
+		}
+	}
+	for key, tombstone := range store.tombstones {
+		if tombstone.sequence > sequence {
+			changes = append(changes, Change{Sequence: tombstone.sequence, Action: ActionDelete, Key: key})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		// Several keys may be changed by the same entry (e.g. CLR), in which case they are ordered by key
+		if changes[i].Sequence == changes[j].Sequence {
+			return changes[i].Key < changes[j].Key
+		}
+		return changes[i].Sequence < changes[j].Sequence
+	})
+	return changes, nil
+}
+
+// trackChange updates the sequence number of the key changed by an entry that has just been applied.
+// Must be called while holding store.mux
+func (store *GDStore) trackChange(entry *Entry) {
+	if entry.Bucket != "" {
+		return
+	}
+	switch entry.Action {
+	case ActionPut, ActionMerge, ActionExpire:
+		store.trackKey(entry.Key, entry.Sequence)
+	case ActionDelete:
+		// Deleting a key that never existed doesn't create a tombstone, unless the entry is a tombstone that was
+		// persisted when the store was consolidated, in which case the key was already removed from keySequences
+		if _, tracked := store.keySequences[entry.Key]; tracked || entry.Timestamp != 0 {
+			store.addTombstone(entry.Key, entry.Sequence, entry.Timestamp)
+		}
+	case ActionRename:
+		store.addTombstone(entry.Key, entry.Sequence, entry.Timestamp)
+		store.trackKey(string(entry.Value), entry.Sequence)
+	case ActionClear:
+		for key := range store.keySequences {
+			store.addTombstone(key, entry.Sequence, entry.Timestamp)
+		}
+	}
+}
+
+// isTombstone returns whether an entry that is about to be written removes a key for which a tombstone must be kept.
+// Must be called while holding store.mux
+func (store *GDStore) isTombstone(entry *Entry) bool {
+	if entry.Bucket != "" || !store.isChangeFeedEnabled() {
+		return false
+	}
+	switch entry.Action {
+	case ActionDelete:
+		_, tracked := store.keySequences[entry.Key]
+		return tracked
+	case ActionRename, ActionClear:
+		return true
+	}
+	return false
+}
+
+// trackKey sets the sequence number of the last change made to a key, if it exists.
+// Must be called while holding store.mux
+func (store *GDStore) trackKey(key string, sequence uint64) {
+	if _, exists := store.data[key]; exists {
+		store.keySequences[key] = sequence
+		delete(store.tombstones, key)
+	}
+}
+
+// addTombstone marks a key as removed. Does nothing other than forgetting the key's sequence number if the change
+// feed is disabled. Must be called while holding store.mux
+func (store *GDStore) addTombstone(key string, sequence uint64, timestamp int64) {
+	if !store.isChangeFeedEnabled() {
+		delete(store.keySequences, key)
+		return
+	}
+	if timestamp == 0 {
+		timestamp = time.Now().UnixNano()
+	}
+	delete(store.keySequences, key)
+	store.tombstones[key] = tombstone{sequence: sequence, timestamp: timestamp}
+}
+
+// purgeChanges removes the tombstones that are older than the retention, as well as the sequence numbers of the
+// keys that no longer exist. Must be called while holding store.mux
+func (store *GDStore) purgeChanges(now int64) {
+	for key, tombstone := range store.tombstones {
+		if now-tombstone.timestamp >= int64(store.tombstoneRetention) {
+			delete(store.tombstones, key)
+			if tombstone.sequence > store.sequenceWatermark {
+				store.sequenceWatermark = tombstone.sequence
+			}
+		}
+	}
+	for key := range store.keySequences {
+		if _, exists := store.data[key]; !exists || store.isExpired(key, now) {
+			delete(store.keySequences, key)
+		}
+	}
+}
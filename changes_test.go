@@ -0,0 +1,175 @@
+package gdstore
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func checkChanges(t *testing.T, store *GDStore, since uint64, expectedChanges []Change) {
+	changes, err := store.ChangesSince(since)
+	if err != nil {
+		t.Fatalf("[%s] Unexpected error: %s", t.Name(), err.Error())
+	}
+	if len(changes) != len(expectedChanges) {
+		t.Fatalf("[%s] Expected %d changes since %d, got %+v instead", t.Name(), len(expectedChanges), since, changes)
+	}
+	for i, expectedChange := range expectedChanges {
+		change := changes[i]
+		if change.Sequence != expectedChange.Sequence || change.Action != expectedChange.Action || change.Key != expectedChange.Key || string(change.Value) != string(expectedChange.Value) {
+			t.Errorf("[%s] Expected %+v, got %+v instead", t.Name(), expectedChange, change)
+		}
+	}
+}
+
+func TestGDStore_ChangesSince(t *testing.T) {
+	store := New(TestStoreFile).WithTombstoneRetention(time.Hour)
+	defer deleteTestStoreFile()
+	_ = store.Put("a", []byte("1"))
+	_ = store.Put("b", []byte("2"))
+	_ = store.Put("a", []byte("3"))
+	_ = store.Delete("b")
+	_ = store.Bucket("bucket").Put("ignored", nil)
+	if store.Sequence() != 5 {
+		t.Errorf("[%s] Expected sequence to be 5, got %d instead", t.Name(), store.Sequence())
+	}
+	checkChanges(t, store, 0, []Change{
+		{Sequence: 3, Action: ActionPut, Key: "a", Value: []byte("3")},
+		{Sequence: 4, Action: ActionDelete, Key: "b"},
+	})
+	checkChanges(t, store, 3, []Change{{Sequence: 4, Action: ActionDelete, Key: "b"}})
+	checkChanges(t, store, 5, nil)
+	_ = store.Rename("a", "c", false)
+	checkChanges(t, store, 5, []Change{
+		{Sequence: 6, Action: ActionDelete, Key: "a"},
+		{Sequence: 6, Action: ActionPut, Key: "c", Value: []byte("3")},
+	})
+	_ = store.Clear()
+	checkChanges(t, store, 6, []Change{{Sequence: 7, Action: ActionDelete, Key: "c"}})
+	store.Close()
+}
+
+func TestGDStore_ChangesSinceWithRemovedExpiredKey(t *testing.T) {
+	store := New(TestStoreFile).WithTombstoneRetention(time.Hour)
+	defer deleteTestStoreFile()
+	_ = store.PutWithTTL("a", []byte("1"), time.Millisecond)
+	_ = store.Put("b", []byte("2"))
+	time.Sleep(5 * time.Millisecond)
+	// Getting an expired key removes it
+	if _, exists := store.Get("a"); exists {
+		t.Errorf("[%s] Expected key a to have expired", t.Name())
+	}
+	checkChanges(t, store, 0, []Change{{Sequence: 2, Action: ActionPut, Key: "b", Value: []byte("2")}})
+	store.Close()
+}
+
+func TestGDStore_ChangesSinceWithDeletedKeysThatNeverExisted(t *testing.T) {
+	store := New(TestStoreFile).WithTombstoneRetention(time.Hour)
+	defer deleteTestStoreFile()
+	_ = store.Put("a", []byte("1"))
+	for i := 0; i < 5; i++ {
+		_ = store.Delete(fmt.Sprintf("does-not-exist-%d", i))
+	}
+	checkChanges(t, store, 0, []Change{{Sequence: 1, Action: ActionPut, Key: "a", Value: []byte("1")}})
+	store.Close()
+	store = New(TestStoreFile)
+	checkChanges(t, store, 0, []Change{{Sequence: 1, Action: ActionPut, Key: "a", Value: []byte("1")}})
+	store.Close()
+}
+
+func TestGDStore_ChangesSinceAfterRestart(t *testing.T) {
+	store := New(TestStoreFile).WithTombstoneRetention(time.Hour)
+	defer deleteTestStoreFile()
+	_ = store.Put("a", []byte("1"))
+	_ = store.Put("b", []byte("2"))
+	_ = store.Delete("a")
+	_ = store.Put("c", []byte("3"))
+	_ = store.Delete("c")
+	store.Close()
+	// Re-loading the store consolidates it, so this also makes sure that sequence numbers and tombstones are kept
+	// when the store is consolidated
+	store = New(TestStoreFile)
+	if store.Sequence() != 5 {
+		t.Errorf("[%s] Expected sequence to be 5, got %d instead", t.Name(), store.Sequence())
+	}
+	checkChanges(t, store, 1, []Change{
+		{Sequence: 2, Action: ActionPut, Key: "b", Value: []byte("2")},
+		{Sequence: 3, Action: ActionDelete, Key: "a"},
+		{Sequence: 5, Action: ActionDelete, Key: "c"},
+	})
+	_ = store.Put("d", []byte("4"))
+	checkChanges(t, store, 5, []Change{{Sequence: 6, Action: ActionPut, Key: "d", Value: []byte("4")}})
+	store.Close()
+}
+
+func TestGDStore_ChangesSinceWithPurgedTombstones(t *testing.T) {
+	store := New(TestStoreFile).WithTombstoneRetention(time.Nanosecond)
+	defer deleteTestStoreFile()
+	_ = store.Put("a", []byte("1"))
+	_ = store.Put("b", []byte("2"))
+	_ = store.Delete("a")
+	_ = store.Put("c", []byte("3"))
+	_ = store.Consolidate()
+	if _, err := store.ChangesSince(2); err != ErrChangesTruncated {
+		t.Errorf("[%s] Expected ErrChangesTruncated, got %v instead", t.Name(), err)
+	}
+	checkChanges(t, store, 3, []Change{{Sequence: 4, Action: ActionPut, Key: "c", Value: []byte("3")}})
+	store.Close()
+	store = New(TestStoreFile)
+	if _, err := store.ChangesSince(2); err != ErrChangesTruncated {
+		t.Errorf("[%s] Expected ErrChangesTruncated after re-loading the store, got %v instead", t.Name(), err)
+	}
+	if store.Sequence() != 4 {
+		t.Errorf("[%s] Expected sequence to be 4, got %d instead", t.Name(), store.Sequence())
+	}
+	store.Close()
+}
+
+func TestGDStore_ChangesSinceWithEntriesWithoutSequence(t *testing.T) {
+	defer deleteTestStoreFile()
+	var content []byte
+	for _, entry := range []*Entry{newEntry(ActionPut, "a", []byte("1")), newEntry(ActionPut, "b", []byte("2")), newEntry(ActionPut, "a", []byte("3"))} {
+		content = append(content, entry.toLine()...)
+	}
+	_ = os.WriteFile(TestStoreFile, content, 0644)
+	store := New(TestStoreFile).WithTombstoneRetention(time.Hour)
+	checkChanges(t, store, 0, []Change{
+		{Sequence: 2, Action: ActionPut, Key: "b", Value: []byte("2")},
+		{Sequence: 3, Action: ActionPut, Key: "a", Value: []byte("3")},
+	})
+	_ = store.Put("c", nil)
+	if store.Sequence() != 4 {
+		t.Errorf("[%s] Expected sequence to be 4, got %d instead", t.Name(), store.Sequence())
+	}
+	store.Close()
+}
+
+func TestGDStore_ChangesSinceWithChangeFeedDisabled(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.Put("a", []byte("1"))
+	_ = store.Put("b", []byte("2"))
+	_ = store.Delete("a")
+	if _, err := store.ChangesSince(0); err != ErrChangeFeedDisabled {
+		t.Errorf("[%s] Expected ErrChangeFeedDisabled, got %v instead", t.Name(), err)
+	}
+	store.WithTombstoneRetention(time.Hour)
+	// The deletion of a happened before the change feed was enabled, so consumers must start over
+	if _, err := store.ChangesSince(2); err != ErrChangesTruncated {
+		t.Errorf("[%s] Expected ErrChangesTruncated, got %v instead", t.Name(), err)
+	}
+	checkChanges(t, store, 0, []Change{{Sequence: 2, Action: ActionPut, Key: "b", Value: []byte("2")}})
+	store.Close()
+	// The retention is persisted, so the change feed is still enabled after re-loading the store
+	store = New(TestStoreFile)
+	_ = store.Delete("b")
+	checkChanges(t, store, 3, []Change{{Sequence: 4, Action: ActionDelete, Key: "b"}})
+	store.WithTombstoneRetention(0)
+	store.Close()
+	store = New(TestStoreFile)
+	if _, err := store.ChangesSince(0); err != ErrChangeFeedDisabled {
+		t.Errorf("[%s] Expected ErrChangeFeedDisabled after disabling the change feed, got %v instead", t.Name(), err)
+	}
+	store.Close()
+}
@@ -0,0 +1,91 @@
+package gdstore
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	ErrOutOfBounds = errors.New("value would be out of bounds")
+)
+
+// Increment atomically adds delta to the integer value of a key and returns the new value.
+//
+// If the key doesn't exist, its value is assumed to be 0. The resulting value is persisted as a regular SET,
+// which means that it can also be read with GetInt. If the new value would overflow an int64, ErrOutOfBounds is
+// returned and the value is left untouched.
+func (store *GDStore) Increment(key string, delta int64) (int64, error) {
+	return store.IncrementWithBounds(key, delta, math.MinInt64, math.MaxInt64)
+}
+
+// Decrement atomically subtracts delta from the integer value of a key and returns the new value.
+//
+// See Increment for more details.
+func (store *GDStore) Decrement(key string, delta int64) (int64, error) {
+	return store.updateCounter(key, func(current int64) (int64, bool) {
+		return subtractInt64(current, delta)
+	})
+}
+
+// IncrementWithBounds does the same thing as Increment, but returns ErrOutOfBounds and leaves the value untouched
+// if the new value would be lower than min or higher than max
+func (store *GDStore) IncrementWithBounds(key string, delta, min, max int64) (int64, error) {
+	return store.updateCounter(key, func(current int64) (int64, bool) {
+		result, ok := addInt64(current, delta)
+		return result, ok && result >= min && result <= max
+	})
+}
+
+// updateCounter atomically replaces the integer value of a key by the value returned by update and returns it.
+// If update returns false, the value is left untouched, and its current value is returned along with ErrOutOfBounds.
+func (store *GDStore) updateCounter(key string, update func(current int64) (int64, bool)) (int64, error) {
+	var result int64
+	_, err := store.Compute(key, func(oldValue []byte, exists bool) ([]byte, bool, error) {
+		var current int64
+		if exists {
+			var err error
+			if current, err = int64Converter.parse(oldValue); err != nil {
+				return nil, false, err
+			}
+		}
+		newValue, ok := update(current)
+		if !ok {
+			result = current
+			return nil, false, ErrOutOfBounds
+		}
+		result = newValue
+		return int64Converter.format(result), true, nil
+	})
+	return result, err
+}
+
+// addInt64 returns a+b as well as whether the result could be represented without overflowing
+func addInt64(a, b int64) (int64, bool) {
+	sum := a + b
+	return sum, (b >= 0) == (sum >= a)
+}
+
+// subtractInt64 returns a-b as well as whether the result could be represented without overflowing
+func subtractInt64(a, b int64) (int64, bool) {
+	difference := a - b
+	return difference, (b >= 0) == (difference <= a)
+}
+
+// IncrementFloat atomically adds delta to the floating point value of a key and returns the new value.
+//
+// If the key doesn't exist, its value is assumed to be 0.
+func (store *GDStore) IncrementFloat(key string, delta float64) (float64, error) {
+	var result float64
+	_, err := store.Compute(key, func(oldValue []byte, exists bool) ([]byte, bool, error) {
+		var current float64
+		if exists {
+			var err error
+			if current, err = float64Converter.parse(oldValue); err != nil {
+				return nil, false, err
+			}
+		}
+		result = current + delta
+		return float64Converter.format(result), true, nil
+	})
+	return result, err
+}
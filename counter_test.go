@@ -0,0 +1,103 @@
+package gdstore
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestGDStore_Increment(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	if value, err := store.Increment("counter", 5); err != nil || value != 5 {
+		t.Errorf("[%s] Expected 5 and no error, got %d and %v", t.Name(), value, err)
+	}
+	if value, err := store.Decrement("counter", 2); err != nil || value != 3 {
+		t.Errorf("[%s] Expected 3 and no error, got %d and %v", t.Name(), value, err)
+	}
+	store.Close()
+
+	// Make sure the counter was persisted
+	store = New(TestStoreFile)
+	if value, _, _ := store.GetInt("counter"); value != 3 {
+		t.Errorf("[%s] Expected counter to be 3 after reloading the store, got %d instead", t.Name(), value)
+	}
+	store.Close()
+}
+
+func TestGDStore_IncrementWithNonInt(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.Put("counter", []byte("NaN"))
+	if _, err := store.Increment("counter", 1); err == nil {
+		t.Errorf("[%s] Expected an error because the value is not an int", t.Name())
+	}
+	checkValueForKey(t, store, "counter", []byte("NaN"))
+	store.Close()
+}
+
+func TestGDStore_IncrementConcurrent(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	var wg sync.WaitGroup
+	wg.Add(100)
+	for i := 0; i < 100; i++ {
+		go func() {
+			defer wg.Done()
+			_, _ = store.Increment("counter", 1)
+		}()
+	}
+	wg.Wait()
+	if value, _, _ := store.GetInt("counter"); value != 100 {
+		t.Errorf("[%s] Expected counter to be 100, got %d instead", t.Name(), value)
+	}
+	store.Close()
+}
+
+func TestGDStore_IncrementWithBounds(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_, _ = store.IncrementWithBounds("counter", 2, 0, 3)
+	value, err := store.IncrementWithBounds("counter", 2, 0, 3)
+	if err != ErrOutOfBounds {
+		t.Errorf("[%s] Expected ErrOutOfBounds, got %v instead", t.Name(), err)
+	}
+	if value != 2 {
+		t.Errorf("[%s] Expected the current value (2) to be returned, got %d instead", t.Name(), value)
+	}
+	checkValueForKey(t, store, "counter", []byte("2"))
+	store.Close()
+}
+
+func TestGDStore_IncrementFloat(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_, _ = store.IncrementFloat("counter", 1.5)
+	if value, err := store.IncrementFloat("counter", 0.25); err != nil || value != 1.75 {
+		t.Errorf("[%s] Expected 1.75 and no error, got %f and %v", t.Name(), value, err)
+	}
+	checkValueForKey(t, store, "counter", []byte("1.75"))
+	store.Close()
+}
+
+func TestGDStore_IncrementWithOverflow(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutInt64("max", math.MaxInt64)
+	if value, err := store.Increment("max", 1); err != ErrOutOfBounds || value != math.MaxInt64 {
+		t.Errorf("[%s] Expected ErrOutOfBounds and %d, got %v and %d instead", t.Name(), int64(math.MaxInt64), err, value)
+	}
+	_ = store.PutInt64("min", math.MinInt64)
+	if value, err := store.Decrement("min", 1); err != ErrOutOfBounds || value != math.MinInt64 {
+		t.Errorf("[%s] Expected ErrOutOfBounds and %d, got %v and %d instead", t.Name(), int64(math.MinInt64), err, value)
+	}
+	if _, err := store.Decrement("zero", math.MinInt64); err != ErrOutOfBounds {
+		t.Errorf("[%s] Expected ErrOutOfBounds, got %v instead", t.Name(), err)
+	}
+	_ = store.PutInt64("negative", -1)
+	if value, err := store.Decrement("negative", math.MinInt64); err != nil || value != math.MaxInt64 {
+		t.Errorf("[%s] Expected %d, got %d and %v instead", t.Name(), int64(math.MaxInt64), value, err)
+	}
+	checkValueForKey(t, store, "max", []byte("9223372036854775807"))
+	store.Close()
+}
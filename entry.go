@@ -4,7 +4,9 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 )
 
 var (
@@ -12,14 +14,78 @@ var (
 	ErrBadLine             = errors.New("bad line")
 )
 
+const (
+	// attributeExpiry is the name of the attribute used to persist the expiration time of an entry
+	attributeExpiry = "exp"
+
+	// attributeSlidingTTL is the name of the attribute used to persist the sliding TTL of an entry
+	attributeSlidingTTL = "sttl"
+
+	// attributeBucket is the name of the attribute used to persist the bucket an entry belongs to
+	attributeBucket = "bkt"
+
+	// attributeSequence is the name of the attribute used to persist the sequence number of an entry
+	attributeSequence = "seq"
+
+	// attributeTimestamp is the name of the attribute used to persist the time at which an entry was written
+	attributeTimestamp = "ts"
+
+	// attributeMergeOperator is the name of the attribute used to persist the merge operator of a MRG entry
+	attributeMergeOperator = "op"
+)
+
 type Entry struct {
 	Action Action
 	Key    string
 	Value  []byte
+
+	// Expiry is the time at which the entry expires, in nanoseconds since the Unix epoch. 0 means no expiration.
+	Expiry int64
+
+	// SlidingTTL is the duration by which the expiration of the entry is extended every time it is read.
+	// 0 means that the expiration is fixed.
+	SlidingTTL time.Duration
+
+	// Bucket is the name of the bucket the entry belongs to. Empty if the entry doesn't belong to a bucket.
+	Bucket string
+
+	// MergeOperator is the name of the merge operator used to apply a MRG entry
+	MergeOperator string
+
+	// Sequence is the sequence number of the entry, which is assigned when the entry is written.
+	// 0 means that the entry has yet to be written, or was written by a version that didn't have sequence numbers.
+	Sequence uint64
+
+	// Timestamp is the time at which the entry was written, in nanoseconds since the Unix epoch.
+	// Only set for entries that remove keys, which are kept as tombstones for the change feed (see ChangesSince).
+	Timestamp int64
 }
 
+// toLine converts the entry into a line that can be appended to the store's file.
+//
+// The first three elements of a line are always the action, the key and the value. Optional attributes are
+// appended as name=value elements, which allows older lines (that have no attributes) to still be read.
 func (e *Entry) toLine() []byte {
-	return []byte(fmt.Sprintf("%s,%s,%s\n", e.Action, base64.StdEncoding.EncodeToString([]byte(e.Key)), base64.StdEncoding.EncodeToString(e.Value)))
+	line := fmt.Sprintf("%s,%s,%s", e.Action, base64.StdEncoding.EncodeToString([]byte(e.Key)), base64.StdEncoding.EncodeToString(e.Value))
+	if e.Expiry != 0 {
+		line += fmt.Sprintf(",%s=%d", attributeExpiry, e.Expiry)
+	}
+	if e.SlidingTTL != 0 {
+		line += fmt.Sprintf(",%s=%d", attributeSlidingTTL, e.SlidingTTL)
+	}
+	if e.Bucket != "" {
+		line += fmt.Sprintf(",%s=%s", attributeBucket, base64.StdEncoding.EncodeToString([]byte(e.Bucket)))
+	}
+	if e.Sequence != 0 {
+		line += fmt.Sprintf(",%s=%d", attributeSequence, e.Sequence)
+	}
+	if e.Timestamp != 0 {
+		line += fmt.Sprintf(",%s=%d", attributeTimestamp, e.Timestamp)
+	}
+	if e.MergeOperator != "" {
+		line += fmt.Sprintf(",%s=%s", attributeMergeOperator, base64.StdEncoding.EncodeToString([]byte(e.MergeOperator)))
+	}
+	return []byte(line + "\n")
 }
 
 func newEntry(action Action, key string, value []byte) *Entry {
@@ -44,7 +110,7 @@ func newBulkEntries(action Action, keyValues map[string][]byte) []*Entry {
 
 func newEntryFromLine(line string) (*Entry, error) {
 	elements := strings.Split(line, ",")
-	if len(elements) != 3 {
+	if len(elements) < 3 {
 		return nil, ErrBadLine
 	}
 	keyAsBytes, err := base64.StdEncoding.DecodeString(elements[1])
@@ -56,9 +122,49 @@ func newEntryFromLine(line string) (*Entry, error) {
 	if err != nil {
 		return nil, ErrCannotDecodeElement
 	}
-	return &Entry{
+	entry := &Entry{
 		Action: Action(elements[0]),
 		Key:    key,
 		Value:  value,
-	}, nil
+	}
+	for _, attribute := range elements[3:] {
+		separatorIndex := strings.Index(attribute, "=")
+		if separatorIndex == -1 {
+			return nil, ErrBadLine
+		}
+		// Unknown attributes are ignored so that files written by newer versions can still be read
+		switch attribute[:separatorIndex] {
+		case attributeExpiry:
+			if entry.Expiry, err = strconv.ParseInt(attribute[separatorIndex+1:], 10, 64); err != nil {
+				return nil, ErrCannotDecodeElement
+			}
+		case attributeSlidingTTL:
+			slidingTTL, err := strconv.ParseInt(attribute[separatorIndex+1:], 10, 64)
+			if err != nil {
+				return nil, ErrCannotDecodeElement
+			}
+			entry.SlidingTTL = time.Duration(slidingTTL)
+		case attributeBucket:
+			bucket, err := base64.StdEncoding.DecodeString(attribute[separatorIndex+1:])
+			if err != nil {
+				return nil, ErrCannotDecodeElement
+			}
+			entry.Bucket = string(bucket)
+		case attributeSequence:
+			if entry.Sequence, err = strconv.ParseUint(attribute[separatorIndex+1:], 10, 64); err != nil {
+				return nil, ErrCannotDecodeElement
+			}
+		case attributeTimestamp:
+			if entry.Timestamp, err = strconv.ParseInt(attribute[separatorIndex+1:], 10, 64); err != nil {
+				return nil, ErrCannotDecodeElement
+			}
+		case attributeMergeOperator:
+			mergeOperator, err := base64.StdEncoding.DecodeString(attribute[separatorIndex+1:])
+			if err != nil {
+				return nil, ErrCannotDecodeElement
+			}
+			entry.MergeOperator = string(mergeOperator)
+		}
+	}
+	return entry, nil
 }
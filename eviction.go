@@ -0,0 +1,330 @@
+package gdstore
+
+import (
+	"container/heap"
+	"container/list"
+	"sync"
+	"time"
+)
+
+// EvictionPolicy determines which entries are evicted when the store has reached its maximum size
+type EvictionPolicy string
+
+var (
+	// EvictionPolicyLRU evicts the least recently used entry, where using an entry means reading or writing it
+	EvictionPolicyLRU EvictionPolicy = "LRU"
+
+	// EvictionPolicyLFU evicts the least frequently used entry. Ties are broken by evicting the oldest entry.
+	//
+	// To prevent new entries from always being the next victim, a new entry starts with the same frequency as
+	// the least frequently used entry.
+	EvictionPolicyLFU EvictionPolicy = "LFU"
+
+	// EvictionPolicyFIFO evicts the oldest entry, regardless of how often it is used
+	EvictionPolicyFIFO EvictionPolicy = "FIFO"
+)
+
+// Stats contains counters about what has been removed from the store without being explicitly deleted
+type Stats struct {
+	// Evictions is the number of entries evicted because the store was full
+	Evictions uint64
+
+	// EvictedBytes is the sum of the sizes (key + value) of all evicted entries
+	EvictedBytes uint64
+
+	// Expirations is the number of expired entries removed from memory
+	Expirations uint64
+}
+
+// WithMaxEntries sets the maximum number of entries the store can contain.
+// Once the limit is reached, entries are evicted according to the eviction policy (see WithEvictionPolicy).
+//
+// The limit is enforced on the next write. A value of 0 means that there is no limit, which is the default.
+func (store *GDStore) WithMaxEntries(maxEntries int) *GDStore {
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	store.maxEntries = maxEntries
+	store.initializeEvictionPolicy()
+	return store
+}
+
+// WithMaxBytes sets the maximum size of the store, where the size of an entry is the length of its key plus
+// the length of its value. Once the limit is reached, entries are evicted according to the eviction policy
+// (see WithEvictionPolicy).
+//
+// The limit is enforced on the next write. A value of 0 means that there is no limit, which is the default.
+func (store *GDStore) WithMaxBytes(maxBytes int) *GDStore {
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	store.maxBytes = maxBytes
+	store.initializeEvictionPolicy()
+	return store
+}
+
+// WithEvictionPolicy sets the policy used to choose which entries to evict once the store has reached
+// the limit set by WithMaxEntries or WithMaxBytes.
+//
+// Because the usage history of entries is not persisted, entries that were loaded from the file are initially
+// considered to have been used as much as each other.
+//
+// Defaults to EvictionPolicyLRU
+func (store *GDStore) WithEvictionPolicy(policy EvictionPolicy) *GDStore {
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	store.evictionPolicy = policy
+	store.evictionTracker = nil
+	store.initializeEvictionPolicy()
+	return store
+}
+
+// OnEvict sets a function that is called with the key and the value of every evicted entry.
+//
+// The function is called after the store has been unlocked, which means that it's safe to use the store from it.
+func (store *GDStore) OnEvict(callback func(key string, value []byte)) {
+	store.mux.Lock()
+	store.evictCallback = callback
+	store.mux.Unlock()
+}
+
+// Stats returns counters about what has been removed from the store without being explicitly deleted
+func (store *GDStore) Stats() Stats {
+	store.mux.RLock()
+	defer store.mux.RUnlock()
+	return store.stats
+}
+
+// initializeEvictionPolicy creates the eviction tracker if there is a limit and there is no tracker yet.
+// Must be called while holding store.mux
+func (store *GDStore) initializeEvictionPolicy() {
+	if store.evictionTracker != nil || (store.maxEntries <= 0 && store.maxBytes <= 0) {
+		return
+	}
+	switch store.evictionPolicy {
+	case EvictionPolicyLFU:
+		store.evictionTracker = newLFUTracker()
+	case EvictionPolicyFIFO:
+		store.evictionTracker = newListTracker(false)
+	default:
+		store.evictionTracker = newListTracker(true)
+	}
+	for key := range store.data {
+		store.evictionTracker.add(key)
+	}
+}
+
+// evict removes entries until the store is within its limits, persists their deletion and queues the
+// OnEvict callback. Expired entries are removed before any other entry is evicted.
+// Must be called while holding store.mux
+func (store *GDStore) evict() error {
+	if store.evictionTracker == nil || !store.isFull() {
+		return nil
+	}
+	store.expireEntries(store.removeExpiredEntries(time.Now().UnixNano()))
+	var entries []*Entry
+	for store.isFull() {
+		key, ok := store.evictionTracker.victim()
+		if !ok {
+			break
+		}
+		value := store.data[key]
+		store.remove(key)
+		store.stats.Evictions++
+		store.stats.EvictedBytes += uint64(entrySize(key, value))
+		store.queueCallback(store.evictCallback, key, value)
+		entries = append(entries, newEntry(ActionDelete, key, nil))
+	}
+	return store.appendEntriesToFile(entries)
+}
+
+// isFull returns whether the store has exceeded its maximum number of entries or bytes.
+// Must be called while holding store.mux
+func (store *GDStore) isFull() bool {
+	return (store.maxEntries > 0 && len(store.data) > store.maxEntries) || (store.maxBytes > 0 && store.size > store.maxBytes)
+}
+
+// queueCallback queues a call to a callback, which is executed once the store has been unlocked by
+// runQueuedCallbacks. Does nothing if the callback is nil.
+func (store *GDStore) queueCallback(callback func(key string, value []byte), key string, value []byte) {
+	if callback == nil {
+		return
+	}
+	store.callbacksMux.Lock()
+	store.queuedCallbacks = append(store.queuedCallbacks, func() { callback(key, value) })
+	store.callbacksMux.Unlock()
+}
+
+// runQueuedCallbacks runs all callbacks queued by queueCallback and delivers the events queued by recordChange.
+// Must NOT be called while holding store.mux
+func (store *GDStore) runQueuedCallbacks() {
+	store.callbacksMux.Lock()
+	callbacks := store.queuedCallbacks
+	store.queuedCallbacks = nil
+	store.callbacksMux.Unlock()
+	for _, callback := range callbacks {
+		callback()
+	}
+	store.dispatchEvents()
+}
+
+// entrySize returns the size of an entry as counted towards the limit set by WithMaxBytes
+func entrySize(key string, value []byte) int {
+	return len(key) + len(value)
+}
+
+// evictionTracker keeps track of the usage of keys in order to pick which key to evict
+type evictionTracker interface {
+	// add starts tracking a new key
+	add(key string)
+	// use marks a key as used, either because it was read or because its value was updated
+	use(key string)
+	// remove stops tracking a key
+	remove(key string)
+	// victim returns the key that should be evicted next
+	victim() (string, bool)
+}
+
+// listTracker is the evictionTracker used for both EvictionPolicyLRU and EvictionPolicyFIFO.
+// The front of the list is the most recent key, and the back of the list is the next victim.
+type listTracker struct {
+	moveOnUse bool
+	order     *list.List
+	elements  map[string]*list.Element
+	mutex     sync.Mutex
+}
+
+func newListTracker(moveOnUse bool) *listTracker {
+	return &listTracker{
+		moveOnUse: moveOnUse,
+		order:     list.New(),
+		elements:  make(map[string]*list.Element),
+	}
+}
+
+func (tracker *listTracker) add(key string) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	if _, exists := tracker.elements[key]; !exists {
+		tracker.elements[key] = tracker.order.PushFront(key)
+	}
+}
+
+func (tracker *listTracker) use(key string) {
+	if !tracker.moveOnUse {
+		return
+	}
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	if element, exists := tracker.elements[key]; exists {
+		tracker.order.MoveToFront(element)
+	}
+}
+
+func (tracker *listTracker) remove(key string) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	if element, exists := tracker.elements[key]; exists {
+		tracker.order.Remove(element)
+		delete(tracker.elements, key)
+	}
+}
+
+func (tracker *listTracker) victim() (string, bool) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	if element := tracker.order.Back(); element != nil {
+		return element.Value.(string), true
+	}
+	return "", false
+}
+
+// lfuTracker is the evictionTracker used for EvictionPolicyLFU
+type lfuTracker struct {
+	items    lfuHeap
+	byKey    map[string]*lfuItem
+	sequence uint64
+	mutex    sync.Mutex
+}
+
+type lfuItem struct {
+	key       string
+	frequency uint64
+	sequence  uint64
+	index     int
+}
+
+func newLFUTracker() *lfuTracker {
+	return &lfuTracker{byKey: make(map[string]*lfuItem)}
+}
+
+func (tracker *lfuTracker) add(key string) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	if _, exists := tracker.byKey[key]; !exists {
+		tracker.sequence++
+		item := &lfuItem{key: key, frequency: 1, sequence: tracker.sequence}
+		if len(tracker.items) > 0 {
+			item.frequency = tracker.items[0].frequency
+		}
+		tracker.byKey[key] = item
+		heap.Push(&tracker.items, item)
+	}
+}
+
+func (tracker *lfuTracker) use(key string) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	if item, exists := tracker.byKey[key]; exists {
+		item.frequency++
+		heap.Fix(&tracker.items, item.index)
+	}
+}
+
+func (tracker *lfuTracker) remove(key string) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	if item, exists := tracker.byKey[key]; exists {
+		heap.Remove(&tracker.items, item.index)
+		delete(tracker.byKey, key)
+	}
+}
+
+func (tracker *lfuTracker) victim() (string, bool) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	if len(tracker.items) == 0 {
+		return "", false
+	}
+	return tracker.items[0].key, true
+}
+
+// lfuHeap is a min-heap of lfuItem ordered by frequency, then by sequence
+type lfuHeap []*lfuItem
+
+func (h lfuHeap) Len() int { return len(h) }
+
+func (h lfuHeap) Less(i, j int) bool {
+	if h[i].frequency == h[j].frequency {
+		return h[i].sequence < h[j].sequence
+	}
+	return h[i].frequency < h[j].frequency
+}
+
+func (h lfuHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *lfuHeap) Push(x interface{}) {
+	item := x.(*lfuItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *lfuHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return item
+}
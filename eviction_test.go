@@ -0,0 +1,76 @@
+package gdstore
+
+import (
+	"testing"
+)
+
+func TestGDStore_WithMaxEntriesAndLRU(t *testing.T) {
+	store := New(TestStoreFile).WithMaxEntries(2).WithEvictionPolicy(EvictionPolicyLRU)
+	defer deleteTestStoreFile()
+	_ = store.Put("1", []byte("one"))
+	_ = store.Put("2", []byte("two"))
+	store.Get("1")
+	_ = store.Put("3", []byte("three"))
+	checkKeyNotExists(t, store, "2")
+	checkValueForKey(t, store, "1", []byte("one"))
+	checkValueForKey(t, store, "3", []byte("three"))
+	store.Close()
+
+	// Make sure the eviction was persisted
+	store = New(TestStoreFile)
+	checkKeyNotExists(t, store, "2")
+	if store.Count() != 2 {
+		t.Errorf("[%s] Expected to have 2 entries, but got %d instead", t.Name(), store.Count())
+	}
+	store.Close()
+}
+
+func TestGDStore_WithMaxEntriesAndLFU(t *testing.T) {
+	store := New(TestStoreFile).WithMaxEntries(2).WithEvictionPolicy(EvictionPolicyLFU)
+	defer deleteTestStoreFile()
+	_ = store.Put("1", []byte("one"))
+	_ = store.Put("2", []byte("two"))
+	store.Get("1")
+	store.Get("1")
+	store.Get("2")
+	_ = store.Put("3", []byte("three"))
+	checkKeyNotExists(t, store, "2")
+	checkValueForKey(t, store, "1", []byte("one"))
+	checkValueForKey(t, store, "3", []byte("three"))
+	store.Close()
+}
+
+func TestGDStore_WithMaxEntriesAndFIFO(t *testing.T) {
+	store := New(TestStoreFile).WithMaxEntries(2).WithEvictionPolicy(EvictionPolicyFIFO)
+	defer deleteTestStoreFile()
+	_ = store.Put("1", []byte("one"))
+	_ = store.Put("2", []byte("two"))
+	store.Get("1")
+	_ = store.Put("3", []byte("three"))
+	checkKeyNotExists(t, store, "1")
+	checkValueForKey(t, store, "2", []byte("two"))
+	checkValueForKey(t, store, "3", []byte("three"))
+	store.Close()
+}
+
+func TestGDStore_WithMaxBytes(t *testing.T) {
+	store := New(TestStoreFile).WithMaxBytes(10)
+	defer deleteTestStoreFile()
+	var evictedKeys []string
+	store.OnEvict(func(key string, value []byte) {
+		evictedKeys = append(evictedKeys, key)
+	})
+	_ = store.Put("1", []byte("1234"))
+	_ = store.Put("2", []byte("1234"))
+	_ = store.PutAll(map[string][]byte{"3": []byte("1234"), "4": []byte("1234")})
+	if store.Count() != 2 {
+		t.Errorf("[%s] Expected to have 2 entries, but got %d instead", t.Name(), store.Count())
+	}
+	if len(evictedKeys) != 2 || evictedKeys[0] != "1" || evictedKeys[1] != "2" {
+		t.Errorf("[%s] Expected keys '1' and '2' to have been passed to the OnEvict callback, got %v instead", t.Name(), evictedKeys)
+	}
+	if stats := store.Stats(); stats.Evictions != 2 || stats.EvictedBytes != 10 {
+		t.Errorf("[%s] Expected 2 evictions for 10 bytes, got %d evictions for %d bytes instead", t.Name(), stats.Evictions, stats.EvictedBytes)
+	}
+	store.Close()
+}
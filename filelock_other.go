@@ -0,0 +1,14 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!windows
+
+package gdstore
+
+import (
+	"os"
+)
+
+// lockExclusively does nothing, because file locks are not supported on this platform. As a result, leases only
+// coordinate the goroutines of a single process.
+func lockExclusively(_ *os.File) error {
+	return nil
+}
@@ -0,0 +1,19 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package gdstore
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockExclusively acquires an exclusive lock on a file, waiting for it to be released if another process holds it.
+// The lock is released when the file is closed.
+func lockExclusively(file *os.File) error {
+	for {
+		if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != syscall.EINTR {
+			return err
+		}
+	}
+}
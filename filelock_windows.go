@@ -0,0 +1,25 @@
+//go:build windows
+// +build windows
+
+package gdstore
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const lockfileExclusiveLock = 0x2
+
+var procLockFileEx = syscall.NewLazyDLL("kernel32.dll").NewProc("LockFileEx")
+
+// lockExclusively acquires an exclusive lock on a file, waiting for it to be released if another process holds it.
+// The lock is released when the file is closed.
+func lockExclusively(file *os.File) error {
+	overlapped := new(syscall.Overlapped)
+	result, _, err := procLockFileEx.Call(file.Fd(), lockfileExclusiveLock, 0, 1, 0, uintptr(unsafe.Pointer(overlapped)))
+	if result == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package gdstore
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrFullTextIndexNotEnabled = errors.New("full-text index is not enabled")
+)
+
+// fullTextIndex is an inverted index of the words contained in the values of a store
+type fullTextIndex struct {
+	// postings contains, for each term, the number of times the term appears in the value of each key
+	postings map[string]map[string]int
+
+	// termsByKey contains the distinct terms of the value of each key
+	termsByKey map[string][]string
+
+	// terms contains every term in lexicographic order, which is used for prefix queries
+	terms *skipList
+}
+
+// WithFullTextIndex sets whether GDStore should maintain a full-text index of its values, which is required
+// to use Search.
+//
+// Values are split into lowercase words made of letters and numbers. Values that aren't valid UTF-8 are not indexed.
+//
+// Defaults to false
+func (store *GDStore) WithFullTextIndex(enabled bool) *GDStore {
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	if !enabled {
+		store.fullTextIndex = nil
+	} else if store.fullTextIndex == nil {
+		store.fullTextIndex = newFullTextIndex()
+		for key, value := range store.data {
+			store.fullTextIndex.add(key, value)
+		}
+	}
+	return store
+}
+
+// Search returns the keys whose value matches a query, from the most relevant to the least relevant.
+//
+// A query is a list of words, all of which must be in the value for it to match. Words ending with * match any word
+// starting with the same prefix, and OR can be used to match either of two groups of words. For instance,
+// "quick brown OR lazy dog*" matches values that contain both "quick" and "brown", or values that contain "lazy"
+// and a word starting with "dog". Searches are case-insensitive.
+//
+// Relevance is determined by how often the words of the query appear in the value, with rarer words weighing more.
+//
+// Returns ErrFullTextIndexNotEnabled if the full-text index hasn't been enabled with WithFullTextIndex.
+func (store *GDStore) Search(query string) ([]string, error) {
+	store.mux.RLock()
+	defer store.mux.RUnlock()
+	if store.fullTextIndex == nil {
+		return nil, ErrFullTextIndexNotEnabled
+	}
+	now := time.Now().UnixNano()
+	scores := make(map[string]float64)
+	for _, group := range parseSearchQuery(query) {
+		for key, score := range store.fullTextIndex.searchAll(group) {
+			if !store.isExpired(key, now) && score > scores[key] {
+				scores[key] = score
+			}
+		}
+	}
+	keys := make([]string, 0, len(scores))
+	for key := range scores {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if scores[keys[i]] == scores[keys[j]] {
+			return keys[i] < keys[j]
+		}
+		return scores[keys[i]] > scores[keys[j]]
+	})
+	return keys, nil
+}
+
+// parseSearchQuery splits a query into groups of words separated by OR
+func parseSearchQuery(query string) [][]string {
+	var groups [][]string
+	var group []string
+	for _, word := range strings.Fields(query) {
+		if word == "OR" {
+			if len(group) > 0 {
+				groups = append(groups, group)
+			}
+			group = nil
+			continue
+		}
+		terms := tokenize(word)
+		// Only the last term of a word can be a prefix (e.g. "e-mail*" is "e" and "mail*")
+		if strings.HasSuffix(word, "*") && len(terms) > 0 {
+			terms[len(terms)-1] += "*"
+		}
+		group = append(group, terms...)
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// tokenize splits a text into lowercase words made of letters and numbers
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func newFullTextIndex() *fullTextIndex {
+	return &fullTextIndex{
+		postings:   make(map[string]map[string]int),
+		termsByKey: make(map[string][]string),
+		terms:      newSkipList(),
+	}
+}
+
+// add indexes the words of a value
+func (index *fullTextIndex) add(key string, value []byte) {
+	if !utf8.Valid(value) {
+		return
+	}
+	frequencies := make(map[string]int)
+	for _, term := range tokenize(string(value)) {
+		frequencies[term]++
+	}
+	terms := make([]string, 0, len(frequencies))
+	for term, frequency := range frequencies {
+		if index.postings[term] == nil {
+			index.postings[term] = make(map[string]int)
+			index.terms.insert(term)
+		}
+		index.postings[term][key] = frequency
+		terms = append(terms, term)
+	}
+	if len(terms) > 0 {
+		index.termsByKey[key] = terms
+	}
+}
+
+// remove removes the words of the value of a key from the index
+func (index *fullTextIndex) remove(key string) {
+	for _, term := range index.termsByKey[key] {
+		delete(index.postings[term], key)
+		if len(index.postings[term]) == 0 {
+			delete(index.postings, term)
+			index.terms.remove(term)
+		}
+	}
+	delete(index.termsByKey, key)
+}
+
+// searchAll returns the keys that match every term of a group, along with their score
+func (index *fullTextIndex) searchAll(terms []string) map[string]float64 {
+	var scores map[string]float64
+	for _, term := range terms {
+		termScores := index.searchTerm(term)
+		if scores == nil {
+			scores = termScores
+			continue
+		}
+		for key, score := range scores {
+			if termScore, matches := termScores[key]; matches {
+				scores[key] = score + termScore
+			} else {
+				delete(scores, key)
+			}
+		}
+	}
+	return scores
+}
+
+// searchTerm returns the keys that contain a term, along with their score.
+// If the term ends with *, it's treated as a prefix.
+func (index *fullTextIndex) searchTerm(term string) map[string]float64 {
+	scores := make(map[string]float64)
+	if strings.HasSuffix(term, "*") {
+		prefix := strings.TrimSuffix(term, "*")
+		for node := index.terms.seek(prefix); node != nil && strings.HasPrefix(node.key, prefix); node = node.next[0] {
+			for key, score := range index.scoreTerm(node.key) {
+				scores[key] += score
+			}
+		}
+		return scores
+	}
+	return index.scoreTerm(term)
+}
+
+// scoreTerm returns the keys that contain a term, scored using TF-IDF
+func (index *fullTextIndex) scoreTerm(term string) map[string]float64 {
+	postings := index.postings[term]
+	scores := make(map[string]float64, len(postings))
+	inverseDocumentFrequency := math.Log(1 + float64(len(index.termsByKey))/float64(len(postings)))
+	for key, frequency := range postings {
+		scores[key] = float64(frequency) * inverseDocumentFrequency
+	}
+	return scores
+}
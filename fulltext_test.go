@@ -0,0 +1,59 @@
+package gdstore
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	groups := parseSearchQuery("Quick brown OR lazy dog* OR e-mail* *")
+	expected := [][]string{{"quick", "brown"}, {"lazy", "dog*"}, {"e", "mail*"}}
+	if !reflect.DeepEqual(groups, expected) {
+		t.Errorf("Expected %v, got %v instead", expected, groups)
+	}
+}
+
+func TestGDStore_Search(t *testing.T) {
+	store := New(TestStoreFile).WithFullTextIndex(true)
+	defer deleteTestStoreFile()
+	_ = store.PutAll(map[string][]byte{
+		"1": []byte("The quick brown fox jumps over the lazy dog"),
+		"2": []byte("The lazy dog sleeps. Lazy, lazy dog!"),
+		"3": []byte("A quick brown cat"),
+		"4": {0xff, 0xfe},
+	})
+	if keys, _ := store.Search("quick brown"); !reflect.DeepEqual(keys, []string{"1", "3"}) && !reflect.DeepEqual(keys, []string{"3", "1"}) {
+		t.Errorf("[%s] Expected keys 1 and 3, got %v instead", t.Name(), keys)
+	}
+	// Key 2 contains "lazy" three times, so it should be more relevant than key 1
+	if keys, _ := store.Search("LAZY"); !reflect.DeepEqual(keys, []string{"2", "1"}) {
+		t.Errorf("[%s] Expected [2 1], got %v instead", t.Name(), keys)
+	}
+	if keys, _ := store.Search("sleep*"); !reflect.DeepEqual(keys, []string{"2"}) {
+		t.Errorf("[%s] Expected [2], got %v instead", t.Name(), keys)
+	}
+	if keys, _ := store.Search("cat OR sleeps"); len(keys) != 2 {
+		t.Errorf("[%s] Expected keys 2 and 3, got %v instead", t.Name(), keys)
+	}
+	if keys, _ := store.Search("cat fox"); len(keys) != 0 {
+		t.Errorf("[%s] Expected no keys, got %v instead", t.Name(), keys)
+	}
+	_ = store.Put("3", []byte("A slow cat"))
+	if keys, _ := store.Search("quick"); !reflect.DeepEqual(keys, []string{"1"}) {
+		t.Errorf("[%s] Expected [1], got %v instead", t.Name(), keys)
+	}
+	_ = store.Delete("2")
+	if keys, _ := store.Search("sleep*"); len(keys) != 0 {
+		t.Errorf("[%s] Expected no keys, got %v instead", t.Name(), keys)
+	}
+	store.Close()
+}
+
+func TestGDStore_SearchWithoutFullTextIndex(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	if _, err := store.Search("quick"); err != ErrFullTextIndexNotEnabled {
+		t.Errorf("[%s] Expected ErrFullTextIndexNotEnabled, got %v instead", t.Name(), err)
+	}
+	store.Close()
+}
@@ -2,9 +2,14 @@ package gdstore
 
 import (
 	"bufio"
+	"errors"
 	"os"
-	"strconv"
 	"sync"
+	"time"
+)
+
+var (
+	ErrKeyNotFound = errors.New("key not found")
 )
 
 type GDStore struct {
@@ -32,18 +37,102 @@ type GDStore struct {
 	// Defaults to true
 	persistence bool
 
-	file   *os.File
-	writer *bufio.Writer
-	data   map[string][]byte
-	mux    sync.RWMutex
+	file     *os.File
+	writer   *bufio.Writer
+	data     map[string][]byte
+	expiries map[string]int64
+	mux      sync.RWMutex
+	keyLocks [numberOfKeyLockStripes]sync.Mutex
+
+	// slidingTTLs contains the TTL of every key whose expiration is extended on read
+	slidingTTLs map[string]time.Duration
+
+	// persistedExpiries contains the last expiration persisted for every key that has a sliding TTL, which is
+	// used to avoid persisting a renewal every single time a key is read
+	persistedExpiries map[string]int64
+
+	// size is the sum of the length of every key and every value in data
+	size int
+
+	maxEntries      int
+	maxBytes        int
+	evictionPolicy  EvictionPolicy
+	evictionTracker evictionTracker
+	stats           Stats
+
+	// orderedIndex contains every key in data in lexicographic order. nil unless WithOrderedIndex(true) was used.
+	orderedIndex *skipList
+
+	// indexes contains the secondary indexes created with CreateIndex
+	indexes map[string]*secondaryIndex
+
+	// fullTextIndex is the inverted index used by Search. nil unless WithFullTextIndex(true) was used.
+	fullTextIndex *fullTextIndex
+
+	// buckets contains the entries of every bucket, indexed by bucket name
+	buckets map[string]map[string][]byte
+
+	// sortedSets contains the index of every sorted set, indexed by the name of the sorted set's bucket
+	sortedSets map[string]*sortedSetIndex
+
+	// queues contains the index of every queue, indexed by the name of the queue's bucket
+	queues map[string]*queueIndex
+
+	janitorStop    chan struct{}
+	expireCallback func(key string, value []byte)
+	evictCallback  func(key string, value []byte)
+
+	// sequence is the last sequence number assigned to an entry
+	sequence uint64
+
+	// sequenceWatermark is the highest sequence number of the changes that have been purged. Changes since a lower
+	// sequence number can no longer be retrieved with ChangesSince.
+	sequenceWatermark uint64
+
+	// keySequences contains the sequence number of the last change made to every key
+	keySequences map[string]uint64
+
+	// tombstones contains the keys that have been removed, which are kept for the duration of tombstoneRetention
+	tombstones         map[string]tombstone
+	tombstoneRetention time.Duration
+
+	// watchers contains the channels returned by Watch
+	watchers            []*watcher
+	watchBufferSize     int
+	watchOverflowPolicy OverflowPolicy
+	changeCallback      func(event Event)
+
+	// pendingEvents contains the events that have yet to be delivered to the watchers and the OnChange callback
+	pendingEvents []Event
+	dispatchMux   sync.Mutex
+
+	// queuedCallbacks contains the callbacks that must be called once the store has been unlocked
+	queuedCallbacks []func()
+	callbacksMux    sync.Mutex
 }
 
+// ComputeFunc is the function passed to GDStore.Compute.
+//
+// It receives the current value of the key as well as whether the key exists, and returns the new value,
+// whether the key should be kept (if false, the key is deleted) and an error, which aborts the operation.
+type ComputeFunc func(oldValue []byte, exists bool) (newValue []byte, keep bool, err error)
+
 // New creates a new GDStore
 func New(filePath string) *GDStore {
 	store := &GDStore{
-		FilePath:    filePath,
-		data:        make(map[string][]byte),
-		persistence: true,
+		FilePath:            filePath,
+		data:                make(map[string][]byte),
+		expiries:            make(map[string]int64),
+		slidingTTLs:         make(map[string]time.Duration),
+		persistedExpiries:   make(map[string]int64),
+		buckets:             make(map[string]map[string][]byte),
+		sortedSets:          make(map[string]*sortedSetIndex),
+		queues:              make(map[string]*queueIndex),
+		keySequences:        make(map[string]uint64),
+		tombstones:          make(map[string]tombstone),
+		persistence:         true,
+		watchBufferSize:     DefaultWatchBufferSize,
+		watchOverflowPolicy: OverflowPolicyBlock,
 	}
 	err := store.loadFromDisk()
 	if err != nil {
@@ -73,90 +162,236 @@ func (store *GDStore) WithPersistence(persistence bool) *GDStore {
 // Get returns the value of a key as well as a bool that indicates whether an entry exists for that key.
 //
 // The bool is particularly useful if you want to differentiate between a key that has a nil value, and a
-// key that doesn't exist
+// key that doesn't exist.
+//
+// If the key has a sliding TTL, its expiration is extended.
 func (store *GDStore) Get(key string) (value []byte, ok bool) {
 	store.mux.RLock()
 	value, ok = store.data[key]
+	expired := ok && store.isExpired(key, time.Now().UnixNano())
+	_, sliding := store.slidingTTLs[key]
+	tracker := store.evictionTracker
 	store.mux.RUnlock()
+	if expired {
+		store.deleteExpiredEntry(key)
+		return nil, false
+	}
+	if ok && sliding {
+		store.renew(key)
+	}
+	if ok && tracker != nil {
+		tracker.use(key)
+	}
 	return
 }
 
 // GetString does the same thing as Get, but converts the value to a string
 func (store *GDStore) GetString(key string) (valueAsString string, ok bool) {
-	var value []byte
-	value, ok = store.Get(key)
-	if ok {
-		valueAsString = string(value)
-	}
+	valueAsString, ok, _ = getDecoded(store, key, StringCodec{}.Decode)
 	return
 }
 
-// GetInt does the same thing as Get, but converts the value to an int
+// GetInt does the same thing as Get, but converts the value to an int.
+//
+// Unlike the other accessors, which return a *DecodeError, GetInt returns the *strconv.NumError as is if the value is
+// not an int.
 func (store *GDStore) GetInt(key string) (valueAsInt int, ok bool, err error) {
-	var value string
-	value, ok = store.GetString(key)
-	if ok {
-		valueAsInt, err = strconv.Atoi(value)
+	var data []byte
+	if data, ok = store.Get(key); ok {
+		valueAsInt, err = intConverter.Decode(data)
 	}
 	return
 }
 
-// Put creates an entry or updates the value of an existing key
+// Put creates an entry or updates the value of an existing key.
+// If the key had an expiration, it is removed.
 func (store *GDStore) Put(key string, value []byte) error {
+	defer store.runQueuedCallbacks()
+	unlock := store.lockKeys(key)
+	defer unlock()
 	store.mux.Lock()
 	defer store.mux.Unlock()
-	store.data[key] = value
-	return store.appendEntryToFile(newEntry(ActionPut, key, value))
+	store.set(key, value, 0)
+	if err := store.appendEntryToFile(newEntry(ActionPut, key, value)); err != nil {
+		return err
+	}
+	return store.evict()
 }
 
 // PutAll creates or updates a map of entries
 func (store *GDStore) PutAll(entries map[string][]byte) error {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	defer store.runQueuedCallbacks()
+	unlock := store.lockKeys(keys...)
+	defer unlock()
 	store.mux.Lock()
 	defer store.mux.Unlock()
 	for key, value := range entries {
-		store.data[key] = value
+		store.set(key, value, 0)
 	}
-	return store.appendEntriesToFile(newBulkEntries(ActionPut, entries))
+	if err := store.appendEntriesToFile(newBulkEntries(ActionPut, entries)); err != nil {
+		return err
+	}
+	return store.evict()
 }
 
 // Delete removes a key from the store
 func (store *GDStore) Delete(key string) error {
+	defer store.runQueuedCallbacks()
+	unlock := store.lockKeys(key)
+	defer unlock()
 	store.mux.Lock()
 	defer store.mux.Unlock()
-	delete(store.data, key)
+	store.remove(key)
 	return store.appendEntryToFile(newEntry(ActionDelete, key, nil))
 }
 
+// Compute atomically replaces the value of a key by the value returned by the function passed as parameter.
+//
+// If the function returns keep=false, the key is deleted. If the function returns an error, the store is left
+// untouched and the error is returned. The new value is persisted as a SET or a DEL, and returned.
+//
+// While the function runs, the lock stripe of the key being computed is held (see numberOfKeyLockStripes), so a slow
+// function only blocks writes to the keys that share that stripe rather than the entire store. If the key has an
+// expiration, it is preserved.
+//
+// The function must not write to the store: writing to a key that shares the same stripe, or using an operation that
+// locks every stripe (e.g. Clear, DeleteMatching or DeleteTree), would deadlock. Reading from the store is fine.
+func (store *GDStore) Compute(key string, fn ComputeFunc) ([]byte, error) {
+	defer store.runQueuedCallbacks()
+	unlock := store.lockKeys(key)
+	defer unlock()
+	// The value is read directly rather than through Get, because Get may run callbacks, which must not be run
+	// while holding the key's lock
+	store.mux.RLock()
+	oldValue, exists := store.data[key]
+	exists = exists && !store.isExpired(key, time.Now().UnixNano())
+	store.mux.RUnlock()
+	newValue, keep, err := fn(oldValue, exists)
+	if err != nil {
+		return nil, err
+	}
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	if expiredValue, found := store.data[key]; found && !exists {
+		store.remove(key)
+		store.expireEntries(map[string][]byte{key: expiredValue})
+	}
+	if !keep {
+		if !exists {
+			return nil, nil
+		}
+		store.remove(key)
+		return nil, store.appendEntryToFile(newEntry(ActionDelete, key, nil))
+	}
+	var expiry int64
+	var slidingTTL time.Duration
+	if exists && !store.isExpired(key, time.Now().UnixNano()) {
+		expiry, slidingTTL = store.expiries[key], store.slidingTTLs[key]
+	}
+	store.set(key, newValue, 0)
+	store.setExpiration(key, expiry, slidingTTL)
+	if err := store.appendEntryToFile(store.withExpiration(newEntry(ActionPut, key, newValue))); err != nil {
+		return nil, err
+	}
+	return newValue, store.evict()
+}
+
 // Count returns the total number of entries in the store
 func (store *GDStore) Count() int {
 	store.mux.RLock()
-	length := len(store.data)
+	length := len(store.data) - store.numberOfExpiredEntries(time.Now().UnixNano())
 	store.mux.RUnlock()
 	return length
 }
 
 // Keys returns a list of all keys
 func (store *GDStore) Keys() []string {
-	store.mux.Lock()
-	keys := make([]string, len(store.data))
-	i := 0
+	store.mux.RLock()
+	now := time.Now().UnixNano()
+	keys := make([]string, 0, len(store.data))
 	for k := range store.data {
-		keys[i] = k
-		i++
+		if !store.isExpired(k, now) {
+			keys = append(keys, k)
+		}
 	}
-	store.mux.Unlock()
+	store.mux.RUnlock()
 	return keys
 }
 
-// Values returns a list of all values
+// Values returns a list of all values.
+// If you need to know which key each value belongs to, use ForEach or Iterator instead.
 func (store *GDStore) Values() [][]byte {
-	store.mux.Lock()
-	values := make([][]byte, len(store.data))
-	i := 0
-	for _, v := range store.data {
-		values[i] = v
-		i++
+	store.mux.RLock()
+	now := time.Now().UnixNano()
+	values := make([][]byte, 0, len(store.data))
+	for k, v := range store.data {
+		if !store.isExpired(k, now) {
+			values = append(values, v)
+		}
 	}
-	store.mux.Unlock()
+	store.mux.RUnlock()
 	return values
 }
+
+// exists returns whether a key exists and hasn't expired. Must be called while holding store.mux
+func (store *GDStore) exists(key string) bool {
+	_, exists := store.data[key]
+	return exists && !store.isExpired(key, time.Now().UnixNano())
+}
+
+// set creates or updates an entry in memory. An expiry of 0 means that the entry never expires.
+// Must be called while holding store.mux
+func (store *GDStore) set(key string, value []byte, expiry int64) {
+	oldValue, exists := store.data[key]
+	store.recordChange(ActionPut, key, oldValue, value)
+	if exists {
+		store.size -= entrySize(key, oldValue)
+		if store.evictionTracker != nil {
+			store.evictionTracker.use(key)
+		}
+	} else {
+		if store.evictionTracker != nil {
+			store.evictionTracker.add(key)
+		}
+		if store.orderedIndex != nil {
+			store.orderedIndex.insert(key)
+		}
+	}
+	store.data[key] = value
+	store.size += entrySize(key, value)
+	for _, index := range store.indexes {
+		index.remove(key)
+		index.add(key, value)
+	}
+	if store.fullTextIndex != nil {
+		store.fullTextIndex.remove(key)
+		store.fullTextIndex.add(key, value)
+	}
+	store.setExpiration(key, expiry, 0)
+}
+
+// remove deletes an entry from memory. Must be called while holding store.mux
+func (store *GDStore) remove(key string) {
+	if value, exists := store.data[key]; exists {
+		store.recordChange(ActionDelete, key, value, nil)
+		store.size -= entrySize(key, value)
+		if store.evictionTracker != nil {
+			store.evictionTracker.remove(key)
+		}
+		if store.orderedIndex != nil {
+			store.orderedIndex.remove(key)
+		}
+		for _, index := range store.indexes {
+			index.remove(key)
+		}
+		if store.fullTextIndex != nil {
+			store.fullTextIndex.remove(key)
+		}
+	}
+	delete(store.data, key)
+	store.setExpiration(key, 0, 0)
+}
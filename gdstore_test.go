@@ -1,8 +1,10 @@
 package gdstore
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"sync"
 	"testing"
 )
@@ -168,6 +170,62 @@ func TestGDStore_PutConcurrent(t *testing.T) {
 	store.Close()
 }
 
+func TestGDStore_Compute(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	value, err := store.Compute("key", func(oldValue []byte, exists bool) ([]byte, bool, error) {
+		if exists {
+			t.Errorf("[%s] Expected key 'key' to not exist yet", t.Name())
+		}
+		return []byte("value"), true, nil
+	})
+	if err != nil {
+		t.Errorf("[%s] Expected no error, got %s", t.Name(), err.Error())
+	}
+	if string(value) != "value" {
+		t.Errorf("[%s] Expected Compute to return 'value', got '%s' instead", t.Name(), value)
+	}
+	checkValueForKey(t, store, "key", []byte("value"))
+	_, err = store.Compute("key", func(oldValue []byte, exists bool) ([]byte, bool, error) {
+		return nil, false, errors.New("abort")
+	})
+	if err == nil {
+		t.Errorf("[%s] Expected the error returned by the function to be returned", t.Name())
+	}
+	checkValueForKey(t, store, "key", []byte("value"))
+	_, _ = store.Compute("key", func(oldValue []byte, exists bool) ([]byte, bool, error) {
+		return nil, false, nil
+	})
+	checkKeyNotExists(t, store, "key")
+	store.Close()
+
+	// Make sure the result of Compute was persisted
+	store = New(TestStoreFile)
+	checkKeyNotExists(t, store, "key")
+	store.Close()
+}
+
+func TestGDStore_ComputeConcurrent(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	var wg sync.WaitGroup
+	wg.Add(100)
+	for i := 0; i < 100; i++ {
+		go func() {
+			defer wg.Done()
+			_, _ = store.Compute("counter", func(oldValue []byte, exists bool) ([]byte, bool, error) {
+				counter, _ := strconv.Atoi(string(oldValue))
+				return []byte(strconv.Itoa(counter + 1)), true, nil
+			})
+		}()
+	}
+	wg.Wait()
+	if counter, _, _ := store.GetInt("counter"); counter != 100 {
+		t.Errorf("[%s] Expected counter to be 100, got %d instead", t.Name(), counter)
+	}
+	store.Close()
+}
+
 func TestGDStore_Keys(t *testing.T) {
 	store := New(TestStoreFile)
 	defer deleteTestStoreFile()
@@ -244,6 +302,9 @@ func TestGDStore_GetIntWithNonInt(t *testing.T) {
 	if err == nil {
 		t.Errorf("[%s] Expected key 'test' to return an error because the value is not an int", t.Name())
 	}
+	if _, isNumError := err.(*strconv.NumError); !isNumError {
+		t.Errorf("[%s] Expected a *strconv.NumError, got %T instead", t.Name(), err)
+	}
 	store.Close()
 }
 
@@ -317,4 +378,5 @@ func checkKeyNotExists(t *testing.T, store *GDStore, key string) {
 func deleteTestStoreFile() {
 	_ = os.Remove(TestStoreFile)
 	_ = os.Remove(fmt.Sprintf("%s.bak", TestStoreFile))
+	_ = os.Remove(fmt.Sprintf("%s.lock", TestStoreFile))
 }
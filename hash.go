@@ -0,0 +1,140 @@
+package gdstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+const (
+	hashBucketPrefix = internalBucketPrefix + "hash:"
+)
+
+var (
+	ErrInvalidHashEncoding = errors.New("invalid hash encoding")
+)
+
+// HSet sets the value of a field of the hash stored at a key.
+//
+// Hashes are stored separately from the store's own entries: a key can be both a regular key and a hash, and
+// hashes are not returned by Get, Keys or Count. Each field is persisted as its own entry in an internal bucket of the
+// store, so updating a field doesn't require re-writing the entire hash. When the store is consolidated, all fields
+// of a hash are combined into a single entry.
+func (store *GDStore) HSet(key, field string, value []byte) error {
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	return store.setHashField(key, field, value)
+}
+
+// HGet returns the value of a field of the hash stored at a key as well as a bool that indicates whether the field
+// exists
+func (store *GDStore) HGet(key, field string) (value []byte, ok bool) {
+	store.mux.RLock()
+	value, ok = store.buckets[hashBucketPrefix+key][field]
+	store.mux.RUnlock()
+	return
+}
+
+// HDel removes one or more fields from the hash stored at a key. Fields that don't exist are ignored.
+// The hash is removed once it has no fields left.
+func (store *GDStore) HDel(key string, fields ...string) error {
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	bucket := hashBucketPrefix + key
+	var entries []*Entry
+	for _, field := range fields {
+		if _, exists := store.buckets[bucket][field]; !exists {
+			continue
+		}
+		store.removeFromBucket(bucket, field)
+		entry := newEntry(ActionDelete, field, nil)
+		entry.Bucket = bucket
+		entries = append(entries, entry)
+	}
+	return store.appendBatchToFile(entries)
+}
+
+// HGetAll returns every field of the hash stored at a key. If the hash doesn't exist, an empty map is returned.
+func (store *GDStore) HGetAll(key string) map[string][]byte {
+	store.mux.RLock()
+	defer store.mux.RUnlock()
+	fields := make(map[string][]byte, len(store.buckets[hashBucketPrefix+key]))
+	for field, value := range store.buckets[hashBucketPrefix+key] {
+		fields[field] = value
+	}
+	return fields
+}
+
+// HIncr atomically adds delta to the integer value of a field of the hash stored at a key and returns the new value.
+//
+// If the field doesn't exist, its value is assumed to be 0. If the value of the field cannot be parsed, a *DecodeError
+// is returned, and if the new value would overflow an int64, ErrOutOfBounds is returned along with the current value.
+func (store *GDStore) HIncr(key, field string, delta int64) (int64, error) {
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	var current int64
+	if value, exists := store.buckets[hashBucketPrefix+key][field]; exists {
+		var err error
+		if current, err = int64Converter.parse(value); err != nil {
+			return 0, &DecodeError{Key: field, Err: err}
+		}
+	}
+	result, ok := addInt64(current, delta)
+	if !ok {
+		return current, ErrOutOfBounds
+	}
+	return result, store.setHashField(key, field, int64Converter.format(result))
+}
+
+// setHashField sets the value of a field of a hash and persists it. Must be called while holding store.mux
+func (store *GDStore) setHashField(key, field string, value []byte) error {
+	entry := newEntry(ActionPut, field, value)
+	entry.Bucket = hashBucketPrefix + key
+	store.setInBucket(entry.Bucket, field, value)
+	return store.appendEntryToFile(entry)
+}
+
+// isHashBucket returns whether a bucket contains the fields of a hash
+func isHashBucket(bucket string) bool {
+	return strings.HasPrefix(bucket, hashBucketPrefix)
+}
+
+// encodeHash encodes the fields of a hash as a sequence of length-prefixed fields and values
+func encodeHash(fields map[string][]byte) []byte {
+	var data []byte
+	var length [binary.MaxVarintLen64]byte
+	for field, value := range fields {
+		data = append(data, length[:binary.PutUvarint(length[:], uint64(len(field)))]...)
+		data = append(data, field...)
+		data = append(data, length[:binary.PutUvarint(length[:], uint64(len(value)))]...)
+		data = append(data, value...)
+	}
+	return data
+}
+
+// decodeHash decodes the fields of a hash encoded with encodeHash
+func decodeHash(data []byte) (map[string][]byte, error) {
+	fields := make(map[string][]byte)
+	for len(data) > 0 {
+		field, remaining, err := decodeHashElement(data)
+		if err != nil {
+			return nil, err
+		}
+		value, remaining, err := decodeHashElement(remaining)
+		if err != nil {
+			return nil, err
+		}
+		fields[string(field)] = value
+		data = remaining
+	}
+	return fields, nil
+}
+
+// decodeHashElement decodes a single length-prefixed element and returns it along with the remaining data
+func decodeHashElement(data []byte) ([]byte, []byte, error) {
+	length, n := binary.Uvarint(data)
+	if n <= 0 || uint64(len(data)-n) < length {
+		return nil, nil, ErrInvalidHashEncoding
+	}
+	return data[n : n+int(length)], data[n+int(length):], nil
+}
@@ -0,0 +1,105 @@
+package gdstore
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGDStore_Hash(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.HSet("user:1", "name", []byte("john"))
+	_ = store.HSet("user:1", "email", []byte("john@example.com"))
+	_ = store.HSet("user:1", "name", []byte("jane"))
+	if value, ok := store.HGet("user:1", "name"); !ok || string(value) != "jane" {
+		t.Errorf("[%s] Expected jane, got %s instead", t.Name(), value)
+	}
+	if visits, err := store.HIncr("user:1", "visits", 2); err != nil || visits != 2 {
+		t.Errorf("[%s] Expected 2, got %d and %v instead", t.Name(), visits, err)
+	}
+	if visits, _ := store.HIncr("user:1", "visits", 3); visits != 5 {
+		t.Errorf("[%s] Expected 5, got %d instead", t.Name(), visits)
+	}
+	var decodeError *DecodeError
+	if _, err := store.HIncr("user:1", "name", 1); !errors.As(err, &decodeError) || decodeError.Key != "name" {
+		t.Errorf("[%s] Expected a DecodeError for the field name because it is not an integer, got %v instead", t.Name(), err)
+	}
+	if visits, err := store.HIncr("user:1", "visits", math.MaxInt64); err != ErrOutOfBounds || visits != 5 {
+		t.Errorf("[%s] Expected ErrOutOfBounds and 5, got %v and %d instead", t.Name(), err, visits)
+	}
+	_ = store.HDel("user:1", "email", "does-not-exist")
+	if _, ok := store.HGet("user:1", "email"); ok {
+		t.Errorf("[%s] Expected email to have been deleted", t.Name())
+	}
+	expectedFields := map[string][]byte{"name": []byte("jane"), "visits": []byte("5")}
+	if fields := store.HGetAll("user:1"); !reflect.DeepEqual(fields, expectedFields) {
+		t.Errorf("[%s] Expected %v, got %v instead", t.Name(), expectedFields, fields)
+	}
+	if store.Count() != 0 {
+		t.Errorf("[%s] Expected hashes to not be counted as entries of the store", t.Name())
+	}
+	store.Close()
+	store = New(TestStoreFile)
+	if fields := store.HGetAll("user:1"); !reflect.DeepEqual(fields, expectedFields) {
+		t.Errorf("[%s] Expected %v after re-loading the store, got %v instead", t.Name(), expectedFields, fields)
+	}
+	store.Close()
+	// The store should've been consolidated into a single entry for the entire hash
+	content, _ := os.ReadFile(TestStoreFile)
+	if lines := bytes.Count(content, []byte("\n")); lines != 1 || !bytes.HasPrefix(content, []byte(ActionHash)) {
+		t.Errorf("[%s] Expected the hash to be persisted as a single HSH entry, got:\n%s", t.Name(), content)
+	}
+	store = New(TestStoreFile)
+	if fields := store.HGetAll("user:1"); !reflect.DeepEqual(fields, expectedFields) {
+		t.Errorf("[%s] Expected %v after re-loading the consolidated store, got %v instead", t.Name(), expectedFields, fields)
+	}
+	store.Close()
+}
+
+func TestGDStore_HashWithLargeFields(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	largeValue := []byte(strings.Repeat("a", 100*1024))
+	_ = store.HSet("large", "1", largeValue)
+	_ = store.HSet("large", "2", largeValue)
+	store.Close()
+	store = New(TestStoreFile)
+	if value, ok := store.HGet("large", "2"); !ok || !bytes.Equal(value, largeValue) {
+		t.Errorf("[%s] Expected field 2 to have been loaded", t.Name())
+	}
+	store.Close()
+}
+
+func TestGDStore_HashWithBucketWithSameName(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.Bucket("hash:user:1").Put("name", []byte("john"))
+	store.Close()
+	store = New(TestStoreFile)
+	if fields := store.HGetAll("user:1"); len(fields) != 0 {
+		t.Errorf("[%s] Expected the bucket to not be mistaken for a hash, got %v instead", t.Name(), fields)
+	}
+	if value, ok := store.Bucket("hash:user:1").Get("name"); !ok || string(value) != "john" {
+		t.Errorf("[%s] Expected key 'name' of the bucket to be 'john', got '%s' instead", t.Name(), value)
+	}
+	store.Close()
+	// The bucket must not have been consolidated into a HSH entry
+	if content, _ := os.ReadFile(TestStoreFile); !bytes.HasPrefix(content, []byte(ActionPut)) {
+		t.Errorf("[%s] Expected the bucket to be persisted as a SET entry, got:\n%s", t.Name(), content)
+	}
+}
+
+func TestDecodeHashWithInvalidData(t *testing.T) {
+	if _, err := decodeHash([]byte{5, 'a'}); err != ErrInvalidHashEncoding {
+		t.Errorf("[%s] Expected ErrInvalidHashEncoding, got %v instead", t.Name(), err)
+	}
+	fields := map[string][]byte{"a": []byte("1"), "": nil, "c": []byte("")}
+	if decodedFields, err := decodeHash(encodeHash(fields)); err != nil || len(decodedFields) != 3 || string(decodedFields["a"]) != "1" {
+		t.Errorf("[%s] Expected %v, got %v and %v instead", t.Name(), fields, decodedFields, err)
+	}
+}
@@ -0,0 +1,50 @@
+package gdstore
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// ListHandler returns an http.Handler that serves the result of List as JSON.
+//
+// The following query parameters are supported: prefix, start_after, continuation_token, limit and
+// include_values. For instance, GET /?prefix=user:&limit=50 returns the first 50 keys starting with "user:", and
+// the continuation_token in the response can be passed back to retrieve the next page.
+//
+// Note that the handler doesn't perform any authentication; it's up to you to protect it.
+func (store *GDStore) ListHandler() http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet {
+			http.Error(writer, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		query := request.URL.Query()
+		options := ListOptions{
+			Prefix:            query.Get("prefix"),
+			StartAfter:        query.Get("start_after"),
+			ContinuationToken: query.Get("continuation_token"),
+		}
+		if limit := query.Get("limit"); limit != "" {
+			var err error
+			if options.Limit, err = strconv.Atoi(limit); err != nil {
+				http.Error(writer, "invalid limit", http.StatusBadRequest)
+				return
+			}
+		}
+		if includeValues := query.Get("include_values"); includeValues != "" {
+			var err error
+			if options.IncludeValues, err = strconv.ParseBool(includeValues); err != nil {
+				http.Error(writer, "invalid include_values", http.StatusBadRequest)
+				return
+			}
+		}
+		result, err := store.List(options)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(writer).Encode(result)
+	})
+}
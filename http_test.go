@@ -0,0 +1,32 @@
+package gdstore
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGDStore_ListHandler(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutAll(map[string][]byte{"user:1": []byte("john"), "user:2": []byte("jane"), "group:1": nil})
+	responseRecorder := httptest.NewRecorder()
+	store.ListHandler().ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/?prefix=user:&limit=1&include_values=true", nil))
+	if responseRecorder.Code != http.StatusOK {
+		t.Fatalf("[%s] Expected status code 200, got %d instead", t.Name(), responseRecorder.Code)
+	}
+	var result ListResult
+	if err := json.NewDecoder(responseRecorder.Body).Decode(&result); err != nil {
+		t.Fatalf("[%s] Unexpected error: %s", t.Name(), err.Error())
+	}
+	if len(result.Keys) != 1 || result.Keys[0] != "user:1" || string(result.Values[0]) != "john" || result.ContinuationToken == "" {
+		t.Errorf("[%s] Expected key user:1 with value john and a continuation token, got %+v instead", t.Name(), result)
+	}
+	responseRecorder = httptest.NewRecorder()
+	store.ListHandler().ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodGet, "/?limit=abc", nil))
+	if responseRecorder.Code != http.StatusBadRequest {
+		t.Errorf("[%s] Expected status code 400, got %d instead", t.Name(), responseRecorder.Code)
+	}
+	store.Close()
+}
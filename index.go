@@ -0,0 +1,111 @@
+package gdstore
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+var (
+	ErrIndexAlreadyExists = errors.New("index already exists")
+	ErrIndexNotFound      = errors.New("index not found")
+)
+
+// IndexFunc returns the terms under which an entry should be indexed.
+// For instance, if values are JSON documents, an IndexFunc could return the value of one of their fields.
+//
+// The function is called while the store is locked, so it must not use the store.
+type IndexFunc func(key string, value []byte) []string
+
+type secondaryIndex struct {
+	indexFunc  IndexFunc
+	keysByTerm map[string]map[string]struct{}
+	termsByKey map[string][]string
+}
+
+// CreateIndex creates a secondary index, which allows keys to be looked up by terms derived from their value
+// using LookupIndex.
+//
+// The index is built from the existing entries, and it is then kept up to date on every write. Because indexes
+// are not persisted, they must be re-created every time the store is created.
+//
+// Returns ErrIndexAlreadyExists if there is already an index with the same name.
+func (store *GDStore) CreateIndex(name string, indexFunc IndexFunc) error {
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	if _, exists := store.indexes[name]; exists {
+		return ErrIndexAlreadyExists
+	}
+	if store.indexes == nil {
+		store.indexes = make(map[string]*secondaryIndex)
+	}
+	index := &secondaryIndex{
+		indexFunc:  indexFunc,
+		keysByTerm: make(map[string]map[string]struct{}),
+		termsByKey: make(map[string][]string),
+	}
+	for key, value := range store.data {
+		index.add(key, value)
+	}
+	store.indexes[name] = index
+	return nil
+}
+
+// DropIndex deletes a secondary index. Does nothing if the index doesn't exist.
+func (store *GDStore) DropIndex(name string) {
+	store.mux.Lock()
+	delete(store.indexes, name)
+	store.mux.Unlock()
+}
+
+// LookupIndex returns the keys indexed under a term, in lexicographic order.
+//
+// Returns ErrIndexNotFound if there is no index with the given name.
+func (store *GDStore) LookupIndex(name, term string) ([]string, error) {
+	store.mux.RLock()
+	defer store.mux.RUnlock()
+	index, exists := store.indexes[name]
+	if !exists {
+		return nil, ErrIndexNotFound
+	}
+	now := time.Now().UnixNano()
+	keys := make([]string, 0, len(index.keysByTerm[term]))
+	for key := range index.keysByTerm[term] {
+		if !store.isExpired(key, now) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// add indexes an entry
+func (index *secondaryIndex) add(key string, value []byte) {
+	terms := index.indexFunc(key, value)
+	for _, term := range terms {
+		if index.keysByTerm[term] == nil {
+			index.keysByTerm[term] = make(map[string]struct{})
+		}
+		index.keysByTerm[term][key] = struct{}{}
+	}
+	if len(terms) > 0 {
+		index.termsByKey[key] = terms
+	}
+}
+
+// remove removes an entry from the index
+func (index *secondaryIndex) remove(key string) {
+	for _, term := range index.termsByKey[key] {
+		delete(index.keysByTerm[term], key)
+		if len(index.keysByTerm[term]) == 0 {
+			delete(index.keysByTerm, term)
+		}
+	}
+	delete(index.termsByKey, key)
+}
+
+// reset removes every entry from the index
+func (index *secondaryIndex) reset() {
+	index.keysByTerm = make(map[string]map[string]struct{})
+	index.termsByKey = make(map[string][]string)
+}
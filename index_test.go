@@ -0,0 +1,67 @@
+package gdstore
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func indexByUserID(key string, value []byte) []string {
+	var session struct {
+		UserID string `json:"user_id"`
+	}
+	if err := json.Unmarshal(value, &session); err != nil || session.UserID == "" {
+		return nil
+	}
+	return []string{session.UserID}
+}
+
+func TestGDStore_CreateIndex(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.Put("session:1", []byte(`{"user_id":"john"}`))
+	if err := store.CreateIndex("user", indexByUserID); err != nil {
+		t.Fatalf("[%s] Unexpected error: %s", t.Name(), err.Error())
+	}
+	if err := store.CreateIndex("user", indexByUserID); err != ErrIndexAlreadyExists {
+		t.Errorf("[%s] Expected ErrIndexAlreadyExists, got %v instead", t.Name(), err)
+	}
+	_ = store.PutAll(map[string][]byte{"session:2": []byte(`{"user_id":"jane"}`), "session:3": []byte(`{"user_id":"john"}`)})
+	if keys, _ := store.LookupIndex("user", "john"); !reflect.DeepEqual(keys, []string{"session:1", "session:3"}) {
+		t.Errorf("[%s] Expected [session:1 session:3], got %v instead", t.Name(), keys)
+	}
+	// Updating the value must move the key to its new term
+	_ = store.Put("session:3", []byte(`{"user_id":"jane"}`))
+	_ = store.Delete("session:2")
+	if keys, _ := store.LookupIndex("user", "john"); !reflect.DeepEqual(keys, []string{"session:1"}) {
+		t.Errorf("[%s] Expected [session:1], got %v instead", t.Name(), keys)
+	}
+	if keys, _ := store.LookupIndex("user", "jane"); !reflect.DeepEqual(keys, []string{"session:3"}) {
+		t.Errorf("[%s] Expected [session:3], got %v instead", t.Name(), keys)
+	}
+	if _, err := store.LookupIndex("unknown", "john"); err != ErrIndexNotFound {
+		t.Errorf("[%s] Expected ErrIndexNotFound, got %v instead", t.Name(), err)
+	}
+	store.DropIndex("user")
+	if _, err := store.LookupIndex("user", "john"); err != ErrIndexNotFound {
+		t.Errorf("[%s] Expected ErrIndexNotFound, got %v instead", t.Name(), err)
+	}
+	store.Close()
+}
+
+func TestGDStore_CreateIndexIsRebuiltOnLoad(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.CreateIndex("user", indexByUserID)
+	_ = store.Put("session:1", []byte(`{"user_id":"john"}`))
+	_ = store.Put("session:2", []byte(`{"user_id":"john"}`))
+	_ = store.Delete("session:1")
+	store.Close()
+	if err := store.loadFromDisk(); err != nil {
+		t.Fatalf("[%s] Unexpected error: %s", t.Name(), err.Error())
+	}
+	if keys, _ := store.LookupIndex("user", "john"); !reflect.DeepEqual(keys, []string{"session:2"}) {
+		t.Errorf("[%s] Expected [session:2], got %v instead", t.Name(), keys)
+	}
+	store.Close()
+}
@@ -0,0 +1,17 @@
+//go:build go1.23
+// +build go1.23
+
+package gdstore
+
+import (
+	"iter"
+)
+
+// All returns an iterator over all entries in the store, for use with range-over-func.
+//
+// See Iterator for the consistency guarantees.
+func (store *GDStore) All() iter.Seq2[string, []byte] {
+	return func(yield func(string, []byte) bool) {
+		store.ForEach(yield)
+	}
+}
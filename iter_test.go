@@ -0,0 +1,22 @@
+//go:build go1.23
+// +build go1.23
+
+package gdstore
+
+import (
+	"testing"
+)
+
+func TestGDStore_All(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutAll(map[string][]byte{"1": []byte("one"), "2": []byte("two")})
+	entries := make(map[string]string)
+	for key, value := range store.All() {
+		entries[key] = string(value)
+	}
+	if len(entries) != 2 || entries["1"] != "one" || entries["2"] != "two" {
+		t.Errorf("[%s] Expected all entries to be returned, got %v instead", t.Name(), entries)
+	}
+	store.Close()
+}
@@ -0,0 +1,131 @@
+package gdstore
+
+import (
+	"time"
+)
+
+// iteratorBatchSize is the number of entries an Iterator reads every time it locks the store
+const iteratorBatchSize = 128
+
+// Iterator iterates over the entries of a store without holding the store's lock for the entire iteration.
+//
+// Entries are read in batches, and the store is only locked while a batch is being read. As a result:
+//   - Each key is returned with the value it had when its batch was read
+//   - Every key that exists for the entire duration of the iteration is returned exactly once
+//   - Keys created or deleted during the iteration may or may not be returned
+//
+// If the store has an ordered index (see WithOrderedIndex), keys are returned in lexicographic order.
+// Otherwise, the keys that exist when the iterator is created are copied (but not their values), and they're
+// returned in no particular order.
+type Iterator struct {
+	store *GDStore
+
+	// keys is the list of keys to iterate over when the store has no ordered index
+	keys []string
+
+	// lastKey is the last key read, which is used to read the next batch when the store has an ordered index
+	lastKey string
+	started bool
+
+	batch []iteratorEntry
+	index int
+	done  bool
+}
+
+type iteratorEntry struct {
+	key   string
+	value []byte
+}
+
+// Iterator returns a new Iterator
+func (store *GDStore) Iterator() *Iterator {
+	iterator := &Iterator{store: store, index: -1}
+	store.mux.RLock()
+	if store.orderedIndex == nil {
+		iterator.keys = make([]string, 0, len(store.data))
+		for key := range store.data {
+			iterator.keys = append(iterator.keys, key)
+		}
+	}
+	store.mux.RUnlock()
+	return iterator
+}
+
+// ForEach calls fn for every entry in the store until fn returns false.
+//
+// The store is not locked while fn is executed, so it's safe to use the store from fn.
+// See Iterator for the consistency guarantees.
+func (store *GDStore) ForEach(fn func(key string, value []byte) bool) {
+	iterator := store.Iterator()
+	for iterator.Next() {
+		if !fn(iterator.Key(), iterator.Value()) {
+			return
+		}
+	}
+}
+
+// Next moves the iterator to the next entry, and returns false if there are no entries left
+func (iterator *Iterator) Next() bool {
+	if iterator.done {
+		return false
+	}
+	iterator.index++
+	if iterator.index >= len(iterator.batch) {
+		iterator.readBatch()
+		if len(iterator.batch) == 0 {
+			iterator.done = true
+			return false
+		}
+	}
+	return true
+}
+
+// Key returns the key of the current entry
+func (iterator *Iterator) Key() string {
+	if iterator.index < 0 || iterator.index >= len(iterator.batch) {
+		return ""
+	}
+	return iterator.batch[iterator.index].key
+}
+
+// Value returns the value of the current entry
+func (iterator *Iterator) Value() []byte {
+	if iterator.index < 0 || iterator.index >= len(iterator.batch) {
+		return nil
+	}
+	return iterator.batch[iterator.index].value
+}
+
+// readBatch replaces the current batch by the next entries
+func (iterator *Iterator) readBatch() {
+	store := iterator.store
+	iterator.batch = iterator.batch[:0]
+	iterator.index = 0
+	store.mux.RLock()
+	defer store.mux.RUnlock()
+	now := time.Now().UnixNano()
+	if iterator.keys == nil && store.orderedIndex != nil {
+		start := iterator.lastKey
+		if iterator.started {
+			// The smallest string greater than the last key is the last key followed by a null byte
+			start += "\x00"
+		}
+		for node := store.orderedIndex.seek(start); node != nil && len(iterator.batch) < iteratorBatchSize; node = node.next[0] {
+			if !store.isExpired(node.key, now) {
+				iterator.batch = append(iterator.batch, iteratorEntry{key: node.key, value: store.data[node.key]})
+			}
+		}
+		if len(iterator.batch) > 0 {
+			iterator.lastKey = iterator.batch[len(iterator.batch)-1].key
+			iterator.started = true
+		}
+		return
+	}
+	for len(iterator.keys) > 0 && len(iterator.batch) < iteratorBatchSize {
+		key := iterator.keys[0]
+		iterator.keys = iterator.keys[1:]
+		if value, exists := store.data[key]; exists && !store.isExpired(key, now) {
+			iterator.batch = append(iterator.batch, iteratorEntry{key: key, value: value})
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package gdstore
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGDStore_ForEach(t *testing.T) {
+	for _, orderedIndex := range []bool{false, true} {
+		store := New(TestStoreFile).WithOrderedIndex(orderedIndex)
+		entries := make(map[string][]byte)
+		for i := 0; i < 1000; i++ {
+			entries[fmt.Sprintf("%04d", i)] = []byte(fmt.Sprintf("value_%d", i))
+		}
+		_ = store.PutAll(entries)
+		visited := make(map[string]bool)
+		previousKey := ""
+		store.ForEach(func(key string, value []byte) bool {
+			if string(value) != string(entries[key]) {
+				t.Errorf("[%s][orderedIndex=%v] Expected key '%s' to have value '%s', got '%s' instead", t.Name(), orderedIndex, key, entries[key], value)
+			}
+			if visited[key] {
+				t.Errorf("[%s][orderedIndex=%v] Key '%s' was visited twice", t.Name(), orderedIndex, key)
+			}
+			if orderedIndex && key < previousKey {
+				t.Errorf("[%s][orderedIndex=%v] Expected keys to be in order, but '%s' came after '%s'", t.Name(), orderedIndex, key, previousKey)
+			}
+			visited[key] = true
+			previousKey = key
+			// Writing to the store during the iteration must not deadlock. The key being visited is the one deleted,
+			// because whether a key that is yet to be visited is still returned depends on whether it has already
+			// been read as part of the current batch.
+			_ = store.Delete(key)
+			return true
+		})
+		if len(visited) != 1000 {
+			t.Errorf("[%s][orderedIndex=%v] Expected 1000 entries to have been visited, got %d instead", t.Name(), orderedIndex, len(visited))
+		}
+		if store.Count() != 0 {
+			t.Errorf("[%s][orderedIndex=%v] Expected every entry to have been deleted, got %d entries instead", t.Name(), orderedIndex, store.Count())
+		}
+		store.Close()
+		deleteTestStoreFile()
+	}
+}
+
+func TestGDStore_ForEachStop(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutAll(map[string][]byte{"1": nil, "2": nil, "3": nil})
+	count := 0
+	store.ForEach(func(key string, value []byte) bool {
+		count++
+		return count < 2
+	})
+	if count != 2 {
+		t.Errorf("[%s] Expected iteration to stop after 2 entries, got %d instead", t.Name(), count)
+	}
+	store.Close()
+}
+
+func TestIterator(t *testing.T) {
+	store := New(TestStoreFile).WithOrderedIndex(true)
+	defer deleteTestStoreFile()
+	_ = store.PutAll(map[string][]byte{"": []byte("empty"), "a": []byte("1")})
+	iterator := store.Iterator()
+	var keys []string
+	for iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+	if len(keys) != 2 || keys[0] != "" || keys[1] != "a" {
+		t.Errorf("[%s] Expected keys ['' a], got %q instead", t.Name(), keys)
+	}
+	if iterator.Next() {
+		t.Errorf("[%s] Expected Next to keep returning false once the iterator is done", t.Name())
+	}
+	store.Close()
+}
@@ -0,0 +1,168 @@
+package gdstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidJSONPointer = errors.New("invalid JSON pointer")
+)
+
+// PatchJSON atomically applies a JSON merge patch (RFC 7386) to the JSON document stored at a key and returns the
+// resulting document, which is persisted as a regular SET.
+//
+// Members of the patch replace the members of the document with the same name, and members whose value is null are
+// removed from the document. If the key doesn't exist, the patch is applied to an empty document. If the value of the
+// key is not valid JSON, a *DecodeError is returned and the value is left untouched.
+func (store *GDStore) PatchJSON(key string, mergePatch []byte) ([]byte, error) {
+	var patch interface{}
+	if err := unmarshalJSON(mergePatch, &patch); err != nil {
+		return nil, fmt.Errorf("invalid merge patch: %w", err)
+	}
+	return store.Compute(key, func(oldValue []byte, exists bool) ([]byte, bool, error) {
+		var document interface{}
+		if exists {
+			if err := unmarshalJSON(oldValue, &document); err != nil {
+				return nil, false, &DecodeError{Key: key, Err: err}
+			}
+		}
+		newValue, err := json.Marshal(applyMergePatch(document, patch))
+		return newValue, err == nil, err
+	})
+}
+
+// SetJSONPath atomically sets the value at the location referenced by a JSON pointer (RFC 6901) in the JSON document
+// stored at a key and returns the resulting document, which is persisted as a regular SET.
+//
+// The value is marshaled to JSON; use json.RawMessage if you already have raw JSON. Objects that don't exist along
+// the way are created, and "-" (or the length of the array) can be used to append to an array. An empty pointer
+// replaces the entire document.
+//
+// If the key doesn't exist, the value is set in an empty document. If the value of the key is not valid JSON,
+// a *DecodeError is returned and the value is left untouched.
+func (store *GDStore) SetJSONPath(key, pointer string, value interface{}) ([]byte, error) {
+	tokens, err := parseJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	rawValue, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode value: %w", err)
+	}
+	return store.Compute(key, func(oldValue []byte, exists bool) ([]byte, bool, error) {
+		var document, newNode interface{}
+		if exists {
+			if err := unmarshalJSON(oldValue, &document); err != nil {
+				return nil, false, &DecodeError{Key: key, Err: err}
+			}
+		}
+		// The value is decoded for every call so that the documents never share the same maps or slices
+		if err := unmarshalJSON(rawValue, &newNode); err != nil {
+			return nil, false, err
+		}
+		document, err := setJSONPointer(document, tokens, newNode)
+		if err != nil {
+			return nil, false, err
+		}
+		newValue, err := json.Marshal(document)
+		return newValue, err == nil, err
+	})
+}
+
+// unmarshalJSON decodes a single JSON value while preserving the exact representation of numbers
+func unmarshalJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.New("invalid character after top-level value")
+	}
+	return nil
+}
+
+// applyMergePatch applies a merge patch to a decoded JSON document as described in RFC 7386
+func applyMergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+		} else {
+			targetObject[name] = applyMergePatch(targetObject[name], value)
+		}
+	}
+	return targetObject
+}
+
+// parseJSONPointer splits a JSON pointer into its unescaped reference tokens
+func parseJSONPointer(pointer string) ([]string, error) {
+	if len(pointer) == 0 {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("%w: %s must start with /", ErrInvalidJSONPointer, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, fmt.Errorf("%w: %s contains an invalid escape sequence", ErrInvalidJSONPointer, pointer)
+			}
+		}
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// setJSONPointer sets the value at the location referenced by tokens in a decoded JSON document and returns the
+// resulting document
+func setJSONPointer(document interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	switch node := document.(type) {
+	case nil:
+		return setJSONPointer(make(map[string]interface{}), tokens, value)
+	case map[string]interface{}:
+		child, err := setJSONPointer(node[tokens[0]], tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		node[tokens[0]] = child
+		return node, nil
+	case []interface{}:
+		index := len(node)
+		if tokens[0] != "-" {
+			var err error
+			// Leading zeros are not allowed by RFC 6901
+			if index, err = strconv.Atoi(tokens[0]); err != nil || index < 0 || index > len(node) || (len(tokens[0]) > 1 && tokens[0][0] == '0') {
+				return nil, fmt.Errorf("%w: %s is not a valid index for an array of length %d", ErrInvalidJSONPointer, tokens[0], len(node))
+			}
+		}
+		if index == len(node) {
+			node = append(node, nil)
+		}
+		child, err := setJSONPointer(node[index], tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = child
+		return node, nil
+	default:
+		return nil, fmt.Errorf("%w: cannot reference %s in a value that is neither an object nor an array", ErrInvalidJSONPointer, tokens[0])
+	}
+}
@@ -0,0 +1,89 @@
+package gdstore
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestGDStore_PatchJSON(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.Put("user", []byte(`{"name":"John","age":42,"address":{"city":"Montreal","zip":"H0H 0H0"},"tags":["a"]}`))
+	value, err := store.PatchJSON("user", []byte(`{"age":43,"address":{"zip":null,"country":"Canada"},"tags":["b"],"id":12345678901234567890}`))
+	if err != nil {
+		t.Fatalf("[%s] Unexpected error: %s", t.Name(), err.Error())
+	}
+	expectedValue := []byte(`{"address":{"city":"Montreal","country":"Canada"},"age":43,"id":12345678901234567890,"name":"John","tags":["b"]}`)
+	if string(value) != string(expectedValue) {
+		t.Errorf("[%s] Expected %s, got %s instead", t.Name(), expectedValue, value)
+	}
+	store.Close()
+	store = New(TestStoreFile)
+	checkValueForKey(t, store, "user", expectedValue)
+	store.Close()
+}
+
+func TestGDStore_PatchJSONWhenKeyDoesNotExist(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	value, err := store.PatchJSON("user", []byte(`{"name":"John","age":null}`))
+	if err != nil || string(value) != `{"name":"John"}` {
+		t.Errorf("[%s] Expected {\"name\":\"John\"}, got %s and %v instead", t.Name(), value, err)
+	}
+	store.Close()
+}
+
+func TestGDStore_PatchJSONWithInvalidDocument(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.Put("user", []byte(`{"name":`))
+	var decodeError *DecodeError
+	if _, err := store.PatchJSON("user", []byte(`{"age":42}`)); !errors.As(err, &decodeError) || decodeError.Key != "user" {
+		t.Errorf("[%s] Expected a DecodeError for key user, got %v instead", t.Name(), err)
+	}
+	checkValueForKey(t, store, "user", []byte(`{"name":`))
+	if _, err := store.PatchJSON("user", []byte(`{"age":`)); err == nil {
+		t.Errorf("[%s] Expected an error because the patch is invalid", t.Name())
+	}
+	store.Close()
+}
+
+func TestGDStore_SetJSONPath(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.Put("user", []byte(`{"name":"John","tags":["a","b"]}`))
+	scenarios := []struct {
+		pointer       string
+		value         interface{}
+		expectedValue string
+	}{
+		{pointer: "/name", value: "Jane", expectedValue: `{"name":"Jane","tags":["a","b"]}`},
+		{pointer: "/tags/0", value: "c", expectedValue: `{"name":"Jane","tags":["c","b"]}`},
+		{pointer: "/tags/-", value: "d", expectedValue: `{"name":"Jane","tags":["c","b","d"]}`},
+		{pointer: "/address/city", value: "Montreal", expectedValue: `{"address":{"city":"Montreal"},"name":"Jane","tags":["c","b","d"]}`},
+		{pointer: "/a~1b~0c", value: json.RawMessage(`[1]`), expectedValue: `{"a/b~c":[1],"address":{"city":"Montreal"},"name":"Jane","tags":["c","b","d"]}`},
+		{pointer: "", value: map[string]int{"id": 1}, expectedValue: `{"id":1}`},
+	}
+	for _, scenario := range scenarios {
+		value, err := store.SetJSONPath("user", scenario.pointer, scenario.value)
+		if err != nil || string(value) != scenario.expectedValue {
+			t.Errorf("[%s] Expected %s after setting %s, got %s and %v instead", t.Name(), scenario.expectedValue, scenario.pointer, value, err)
+		}
+	}
+	checkValueForKey(t, store, "user", []byte(`{"id":1}`))
+	store.Close()
+}
+
+func TestGDStore_SetJSONPathWithInvalidPointer(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.Put("user", []byte(`{"name":"John","tags":["a","b"]}`))
+	for _, pointer := range []string{"name", "/name/first", "/tags/3", "/tags/01", "/tags/x", "/a~2"} {
+		if _, err := store.SetJSONPath("user", pointer, "value"); !errors.Is(err, ErrInvalidJSONPointer) {
+			t.Errorf("[%s] Expected ErrInvalidJSONPointer for %s, got %v instead", t.Name(), pointer, err)
+		}
+	}
+	checkValueForKey(t, store, "user", []byte(`{"name":"John","tags":["a","b"]}`))
+	store.Close()
+}
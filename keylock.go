@@ -0,0 +1,59 @@
+package gdstore
+
+import (
+	"hash/fnv"
+	"sort"
+)
+
+// numberOfKeyLockStripes is the number of mutexes keys are distributed across.
+//
+// Operations on keys that hash to different stripes never wait on each other, which means that
+// a slow ComputeFunc only blocks the writers of keys sharing its stripe rather than the entire store.
+const numberOfKeyLockStripes = 256
+
+// keyLockStripe returns the index of the stripe responsible for a given key
+func keyLockStripe(key string) int {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(key))
+	return int(hash.Sum32() % numberOfKeyLockStripes)
+}
+
+// lockKeys acquires the stripe locks of every key passed as parameter and returns a function that releases them.
+//
+// Stripes are always acquired in ascending order to prevent deadlocks between two callers locking overlapping
+// sets of keys. Stripe locks must always be acquired before store.mux, never while holding it.
+func (store *GDStore) lockKeys(keys ...string) (unlock func()) {
+	seen := make(map[int]bool, len(keys))
+	stripes := make([]int, 0, len(keys))
+	for _, key := range keys {
+		stripe := keyLockStripe(key)
+		if !seen[stripe] {
+			seen[stripe] = true
+			stripes = append(stripes, stripe)
+		}
+	}
+	sort.Ints(stripes)
+	for _, stripe := range stripes {
+		store.keyLocks[stripe].Lock()
+	}
+	return func() {
+		for i := len(stripes) - 1; i >= 0; i-- {
+			store.keyLocks[stripes[i]].Unlock()
+		}
+	}
+}
+
+// lockAllKeys acquires every stripe lock and returns a function that releases them.
+//
+// This is meant for operations that don't know which keys they'll modify until they've looked at the data,
+// such as DeleteMatching.
+func (store *GDStore) lockAllKeys() (unlock func()) {
+	for stripe := range store.keyLocks {
+		store.keyLocks[stripe].Lock()
+	}
+	return func() {
+		for stripe := len(store.keyLocks) - 1; stripe >= 0; stripe-- {
+			store.keyLocks[stripe].Unlock()
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package gdstore
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	leaseBucket = internalBucketPrefix + "leases"
+)
+
+var (
+	ErrLeaseHeld = errors.New("lease is held by another owner")
+	ErrLeaseLost = errors.New("lease is no longer held")
+)
+
+// Lease grants its owner exclusive access to a named resource until it expires or is released.
+//
+// Every time a lease is acquired by a new owner, its Token is incremented, which can be used as a fencing token
+// to reject operations from a previous owner whose lease expired while it was still working.
+//
+// Leases are persisted in an internal bucket of the store, so a lease that was held when the application stopped or
+// crashed is still held when it restarts, until it expires.
+//
+// Leases can also coordinate processes using the same file, such as overlapping runs of a cron job: every lease
+// operation takes an exclusive lock on the lock file of the store and re-reads the lease from the store's file
+// before deciding, and the change is flushed to the file before the lock is released.
+type Lease struct {
+	store *GDStore
+
+	Name   string
+	Owner  string
+	Token  uint64
+	Expiry time.Time
+}
+
+// AcquireLease acquires the lease with the given name for an owner for the duration of the TTL.
+//
+// If the lease is held by another owner and hasn't expired, ErrLeaseHeld is returned. If the lease is already held
+// by the same owner, its expiration is extended.
+func (store *GDStore) AcquireLease(name, owner string, ttl time.Duration) (Lease, error) {
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	unlock, err := store.lockStoreFile()
+	if err != nil {
+		return Lease{}, err
+	}
+	defer unlock()
+	if err = store.reloadLeases(); err != nil {
+		return Lease{}, err
+	}
+	now := time.Now()
+	lease, exists, err := store.getLease(name)
+	if err != nil {
+		return Lease{}, err
+	}
+	if exists && lease.Expiry.After(now) {
+		if lease.Owner != owner {
+			return Lease{}, ErrLeaseHeld
+		}
+	} else {
+		lease.Token++
+	}
+	lease.Owner, lease.Expiry = owner, now.Add(ttl)
+	return lease, store.putLease(lease)
+}
+
+// Renew extends the expiration of the lease by the duration of the TTL, starting from now.
+//
+// If the lease has expired or has been acquired by another owner in the meantime, ErrLeaseLost is returned.
+func (lease Lease) Renew(ttl time.Duration) (Lease, error) {
+	lease.store.mux.Lock()
+	defer lease.store.mux.Unlock()
+	unlock, err := lease.store.lockStoreFile()
+	if err != nil {
+		return Lease{}, err
+	}
+	defer unlock()
+	if err = lease.store.checkLease(lease); err != nil {
+		return Lease{}, err
+	}
+	lease.Expiry = time.Now().Add(ttl)
+	return lease, lease.store.putLease(lease)
+}
+
+// Release releases the lease, which lets other owners acquire it right away.
+//
+// If the lease has expired or has been acquired by another owner in the meantime, ErrLeaseLost is returned.
+func (lease Lease) Release() error {
+	lease.store.mux.Lock()
+	defer lease.store.mux.Unlock()
+	unlock, err := lease.store.lockStoreFile()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err = lease.store.checkLease(lease); err != nil {
+		return err
+	}
+	// The lease is kept rather than deleted so that the next token is still greater than this one
+	lease.Owner, lease.Expiry = "", time.Unix(0, 0)
+	return lease.store.putLease(lease)
+}
+
+// checkLease returns ErrLeaseLost if a lease is not the current, unexpired lease with the same name.
+// Must be called while holding store.mux and the lock of the store's file
+func (store *GDStore) checkLease(lease Lease) error {
+	if err := store.reloadLeases(); err != nil {
+		return err
+	}
+	current, exists, err := store.getLease(lease.Name)
+	if err != nil {
+		return err
+	}
+	if !exists || current.Owner != lease.Owner || current.Token != lease.Token || !current.Expiry.After(time.Now()) {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// getLease returns the lease with the given name. Must be called while holding store.mux
+func (store *GDStore) getLease(name string) (Lease, bool, error) {
+	lease := Lease{store: store, Name: name}
+	value, exists := store.buckets[leaseBucket][name]
+	if !exists {
+		return lease, false, nil
+	}
+	// A lease is persisted as <token>,<expiry>,<owner>
+	elements := strings.SplitN(string(value), ",", 3)
+	if len(elements) != 3 {
+		return lease, false, &DecodeError{Key: name, Err: ErrBadLine}
+	}
+	token, err := uint64Converter.parse([]byte(elements[0]))
+	if err != nil {
+		return lease, false, &DecodeError{Key: name, Err: err}
+	}
+	expiry, err := int64Converter.parse([]byte(elements[1]))
+	if err != nil {
+		return lease, false, &DecodeError{Key: name, Err: err}
+	}
+	lease.Token, lease.Expiry, lease.Owner = token, time.Unix(0, expiry), elements[2]
+	return lease, true, nil
+}
+
+// reloadLeases replaces the leases in memory by the last ones written to the store's file, which may have been
+// written by another process. Does nothing if persistence is disabled.
+// Must be called while holding store.mux and the lock of the store's file
+func (store *GDStore) reloadLeases() error {
+	if !store.persistence {
+		return nil
+	}
+	// The store's file is closed so that pending writes are flushed, and so that it's re-opened by the next write
+	// in case another process has consolidated it in the meantime
+	store.Close()
+	file, err := os.Open(store.FilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+	return readEntries(file, func(entry *Entry) error {
+		if entry.Action == ActionPut && entry.Bucket == leaseBucket {
+			store.setInBucket(leaseBucket, entry.Key, entry.Value)
+		}
+		return nil
+	})
+}
+
+// putLease persists a lease and flushes it to the store's file.
+// Must be called while holding store.mux and the lock of the store's file
+func (store *GDStore) putLease(lease Lease) error {
+	value := string(uint64Converter.format(lease.Token)) + "," + string(int64Converter.format(lease.Expiry.UnixNano())) + "," + lease.Owner
+	entry := newEntry(ActionPut, lease.Name, []byte(value))
+	entry.Bucket = leaseBucket
+	store.setInBucket(leaseBucket, lease.Name, entry.Value)
+	if err := store.appendEntryToFile(entry); err != nil {
+		return err
+	}
+	// Other processes only see the lease once it has been written to the file
+	return store.Flush()
+}
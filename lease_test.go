@@ -0,0 +1,148 @@
+package gdstore
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGDStore_AcquireLease(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	lease, err := store.AcquireLease("job", "a", time.Minute)
+	if err != nil || lease.Owner != "a" || lease.Token != 1 {
+		t.Fatalf("[%s] Expected lease to be acquired by a with token 1, got %+v and %v instead", t.Name(), lease, err)
+	}
+	if _, err := store.AcquireLease("job", "b", time.Minute); err != ErrLeaseHeld {
+		t.Errorf("[%s] Expected ErrLeaseHeld, got %v instead", t.Name(), err)
+	}
+	// Acquiring a lease that is already held by the same owner extends it
+	if reacquiredLease, err := store.AcquireLease("job", "a", time.Hour); err != nil || reacquiredLease.Token != 1 || !reacquiredLease.Expiry.After(lease.Expiry) {
+		t.Errorf("[%s] Expected lease to be extended, got %+v and %v instead", t.Name(), reacquiredLease, err)
+	}
+	if err := lease.Release(); err != nil {
+		t.Errorf("[%s] Unexpected error: %s", t.Name(), err.Error())
+	}
+	if err := lease.Release(); err != ErrLeaseLost {
+		t.Errorf("[%s] Expected ErrLeaseLost, got %v instead", t.Name(), err)
+	}
+	if lease, err = store.AcquireLease("job", "b", time.Minute); err != nil || lease.Token != 2 {
+		t.Errorf("[%s] Expected lease to be acquired by b with token 2, got %+v and %v instead", t.Name(), lease, err)
+	}
+	store.Close()
+}
+
+func TestGDStore_AcquireLeaseWithBucketWithSameName(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.Bucket("leases").Put("backup", []byte("not a lease"))
+	if lease, err := store.AcquireLease("backup", "a", time.Minute); err != nil || lease.Token != 1 {
+		t.Errorf("[%s] Expected lease to be acquired by a with token 1, got %+v and %v instead", t.Name(), lease, err)
+	}
+	if value, _ := store.Bucket("leases").Get("backup"); string(value) != "not a lease" {
+		t.Errorf("[%s] Expected the bucket to not have been modified, got '%s' instead", t.Name(), value)
+	}
+	store.Close()
+}
+
+func TestGDStore_AcquireLeaseFromMultipleStoresUsingSameFile(t *testing.T) {
+	// Each store simulates a different process using the same file
+	store := New(TestStoreFile).WithBuffer(true)
+	defer deleteTestStoreFile()
+	lease, err := store.AcquireLease("job", "a", time.Minute)
+	if err != nil || lease.Token != 1 {
+		t.Fatalf("[%s] Expected lease to be acquired by a with token 1, got %+v and %v instead", t.Name(), lease, err)
+	}
+	// Creating the second store consolidates the file, which must not hide the lease of the first store
+	otherStore := New(TestStoreFile).WithBuffer(true)
+	if _, err := otherStore.AcquireLease("job", "b", time.Minute); err != ErrLeaseHeld {
+		t.Errorf("[%s] Expected ErrLeaseHeld, got %v instead", t.Name(), err)
+	}
+	if lease, err = lease.Renew(time.Minute); err != nil {
+		t.Errorf("[%s] Unexpected error: %v", t.Name(), err)
+	}
+	if err = lease.Release(); err != nil {
+		t.Errorf("[%s] Unexpected error: %v", t.Name(), err)
+	}
+	otherLease, err := otherStore.AcquireLease("job", "b", time.Minute)
+	if err != nil || otherLease.Token != 2 {
+		t.Errorf("[%s] Expected lease to be acquired by b with token 2, got %+v and %v instead", t.Name(), otherLease, err)
+	}
+	if _, err := store.AcquireLease("job", "a", time.Minute); err != ErrLeaseHeld {
+		t.Errorf("[%s] Expected ErrLeaseHeld, got %v instead", t.Name(), err)
+	}
+	if err := lease.Release(); err != ErrLeaseLost {
+		t.Errorf("[%s] Expected ErrLeaseLost, got %v instead", t.Name(), err)
+	}
+	store.Close()
+	otherStore.Close()
+}
+
+func TestLease_Renew(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	lease, _ := store.AcquireLease("job", "a", 50*time.Millisecond)
+	renewedLease, err := lease.Renew(time.Minute)
+	if err != nil || !renewedLease.Expiry.After(lease.Expiry) {
+		t.Errorf("[%s] Expected lease to be renewed, got %+v and %v instead", t.Name(), renewedLease, err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if _, err := store.AcquireLease("job", "b", time.Minute); err != ErrLeaseHeld {
+		t.Errorf("[%s] Expected ErrLeaseHeld, got %v instead", t.Name(), err)
+	}
+	store.Close()
+}
+
+func TestLease_Expiration(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	lease, _ := store.AcquireLease("job", "a", 50*time.Millisecond)
+	time.Sleep(60 * time.Millisecond)
+	if _, err := lease.Renew(time.Minute); err != ErrLeaseLost {
+		t.Errorf("[%s] Expected ErrLeaseLost, got %v instead", t.Name(), err)
+	}
+	newLease, err := store.AcquireLease("job", "b", time.Minute)
+	if err != nil || newLease.Token != 2 {
+		t.Errorf("[%s] Expected expired lease to be acquired by b with token 2, got %+v and %v instead", t.Name(), newLease, err)
+	}
+	if err := lease.Release(); err != ErrLeaseLost {
+		t.Errorf("[%s] Expected ErrLeaseLost, got %v instead", t.Name(), err)
+	}
+	store.Close()
+}
+
+func TestLease_Persistence(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_, _ = store.AcquireLease("job", "a", time.Minute)
+	store.Close()
+	store = New(TestStoreFile)
+	if _, err := store.AcquireLease("job", "b", time.Minute); err != ErrLeaseHeld {
+		t.Errorf("[%s] Expected ErrLeaseHeld after re-loading the store, got %v instead", t.Name(), err)
+	}
+	store.Close()
+}
+
+func TestLease_Concurrency(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	var wg sync.WaitGroup
+	var mux sync.Mutex
+	acquired := 0
+	for _, owner := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		wg.Add(1)
+		go func(owner string) {
+			defer wg.Done()
+			if _, err := store.AcquireLease("job", owner, time.Minute); err == nil {
+				mux.Lock()
+				acquired++
+				mux.Unlock()
+			}
+		}(owner)
+	}
+	wg.Wait()
+	if acquired != 1 {
+		t.Errorf("[%s] Expected the lease to be acquired exactly once, got %d instead", t.Name(), acquired)
+	}
+	store.Close()
+}
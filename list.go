@@ -0,0 +1,110 @@
+package gdstore
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+const (
+	// DefaultListLimit is the maximum number of keys returned by List when ListOptions.Limit is not set
+	DefaultListLimit = 1000
+
+	// continuationTokenPrefix is prepended to the last key of a page before encoding it into a continuation token,
+	// so that the format of the token can be changed later without breaking existing tokens
+	continuationTokenPrefix = "v1:"
+)
+
+var (
+	ErrInvalidContinuationToken = errors.New("invalid continuation token")
+)
+
+// ListOptions are the options passed to GDStore.List
+type ListOptions struct {
+	// Prefix restricts the result to keys that start with the given prefix
+	Prefix string `json:"prefix,omitempty"`
+
+	// StartAfter restricts the result to keys that are strictly greater than the given key
+	StartAfter string `json:"start_after,omitempty"`
+
+	// ContinuationToken is the token returned by the previous call to List. If set, StartAfter is ignored.
+	ContinuationToken string `json:"continuation_token,omitempty"`
+
+	// Limit is the maximum number of keys to return. Defaults to DefaultListLimit
+	Limit int `json:"limit,omitempty"`
+
+	// IncludeValues determines whether the values of the keys should be returned as well
+	IncludeValues bool `json:"include_values,omitempty"`
+}
+
+// ListResult is a page of keys returned by GDStore.List
+type ListResult struct {
+	// Keys are the keys of the page, in lexicographic order
+	Keys []string `json:"keys"`
+
+	// Values contains the value of each key in Keys, at the same index. Only set if ListOptions.IncludeValues is true
+	Values [][]byte `json:"values,omitempty"`
+
+	// ContinuationToken must be passed to the next call to List to retrieve the next page.
+	// Empty if there are no pages left.
+	ContinuationToken string `json:"continuation_token,omitempty"`
+}
+
+// List returns a page of keys in lexicographic order.
+//
+// Because a page always starts right after the last key of the previous page, paginating with the continuation
+// token never returns the same key twice and never skips a key that existed for the entire pagination, even if
+// entries are created or deleted between calls.
+//
+// For large stores, it's recommended to enable the ordered index (see WithOrderedIndex).
+func (store *GDStore) List(options ListOptions) (*ListResult, error) {
+	start := options.Prefix
+	startAfter := options.StartAfter
+	if options.ContinuationToken != "" {
+		var err error
+		if startAfter, err = decodeContinuationToken(options.ContinuationToken); err != nil {
+			return nil, err
+		}
+	}
+	if startAfter != "" && startAfter+"\x00" > start {
+		start = startAfter + "\x00"
+	}
+	limit := options.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	result := &ListResult{Keys: []string{}}
+	hasMore := false
+	store.mux.RLock()
+	defer store.mux.RUnlock()
+	store.ascend(start, func(key string) bool {
+		if !strings.HasPrefix(key, options.Prefix) {
+			return false
+		}
+		if len(result.Keys) == limit {
+			hasMore = true
+			return false
+		}
+		result.Keys = append(result.Keys, key)
+		if options.IncludeValues {
+			result.Values = append(result.Values, store.data[key])
+		}
+		return true
+	})
+	if hasMore {
+		result.ContinuationToken = encodeContinuationToken(result.Keys[len(result.Keys)-1])
+	}
+	return result, nil
+}
+
+func encodeContinuationToken(lastKey string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(continuationTokenPrefix + lastKey))
+}
+
+func decodeContinuationToken(token string) (string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || !strings.HasPrefix(string(decoded), continuationTokenPrefix) {
+		return "", ErrInvalidContinuationToken
+	}
+	return strings.TrimPrefix(string(decoded), continuationTokenPrefix), nil
+}
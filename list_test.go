@@ -0,0 +1,62 @@
+package gdstore
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestGDStore_List(t *testing.T) {
+	for _, orderedIndex := range []bool{false, true} {
+		store := New(TestStoreFile).WithOrderedIndex(orderedIndex)
+		entries := make(map[string][]byte)
+		for i := 0; i < 25; i++ {
+			entries[fmt.Sprintf("user:%02d", i)] = []byte(fmt.Sprintf("%d", i))
+		}
+		entries["group:1"] = nil
+		_ = store.PutAll(entries)
+		var keys []string
+		options := ListOptions{Prefix: "user:", Limit: 10}
+		for numberOfPages := 1; ; numberOfPages++ {
+			result, err := store.List(options)
+			if err != nil {
+				t.Fatalf("[%s][orderedIndex=%v] Unexpected error: %s", t.Name(), orderedIndex, err.Error())
+			}
+			keys = append(keys, result.Keys...)
+			if numberOfPages == 1 {
+				// Writes between pages must not cause keys to be skipped or returned twice
+				_ = store.Delete("user:05")
+				_ = store.Put("user:24a", nil)
+			}
+			if result.ContinuationToken == "" {
+				if numberOfPages != 3 {
+					t.Errorf("[%s][orderedIndex=%v] Expected 3 pages, got %d instead", t.Name(), orderedIndex, numberOfPages)
+				}
+				break
+			}
+			options.ContinuationToken = result.ContinuationToken
+		}
+		if len(keys) != 26 || keys[0] != "user:00" || keys[24] != "user:24" || keys[25] != "user:24a" {
+			t.Errorf("[%s][orderedIndex=%v] Expected 26 keys from user:00 to user:24a, got %v instead", t.Name(), orderedIndex, keys)
+		}
+		store.Close()
+		deleteTestStoreFile()
+	}
+}
+
+func TestGDStore_ListWithStartAfterAndValues(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutAll(map[string][]byte{"a": []byte("1"), "b": []byte("2"), "c": []byte("3")})
+	result, _ := store.List(ListOptions{StartAfter: "a", IncludeValues: true})
+	if !reflect.DeepEqual(result.Keys, []string{"b", "c"}) || string(result.Values[0]) != "2" || string(result.Values[1]) != "3" {
+		t.Errorf("[%s] Expected keys [b c] with values [2 3], got %v and %q instead", t.Name(), result.Keys, result.Values)
+	}
+	if result.ContinuationToken != "" {
+		t.Errorf("[%s] Expected no continuation token, got %s instead", t.Name(), result.ContinuationToken)
+	}
+	if _, err := store.List(ListOptions{ContinuationToken: "invalid"}); err != ErrInvalidContinuationToken {
+		t.Errorf("[%s] Expected ErrInvalidContinuationToken, got %v instead", t.Name(), err)
+	}
+	store.Close()
+}
@@ -0,0 +1,109 @@
+package gdstore
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	// MergeOperatorAppend is the name of the built-in merge operator used by Append
+	MergeOperatorAppend = "append"
+)
+
+var (
+	ErrUnknownMergeOperator = errors.New("unknown merge operator")
+
+	mergeOperators = map[string]MergeFunc{
+		MergeOperatorAppend: func(existingValue, operand []byte) []byte {
+			newValue := make([]byte, 0, len(existingValue)+len(operand))
+			return append(append(newValue, existingValue...), operand...)
+		},
+	}
+	mergeOperatorsMux sync.RWMutex
+)
+
+// MergeFunc is a merge operator, which combines the existing value of a key with an operand and returns the new value.
+//
+// existingValue is nil if the key doesn't exist. A merge operator must be deterministic, as it is called again with
+// the same arguments when the store is loaded, and it must not modify existingValue or operand.
+type MergeFunc func(existingValue, operand []byte) []byte
+
+// RegisterMergeOperator registers a merge operator under a name, which can then be used with GDStore.Merge.
+// If an operator with the same name has already been registered, it is replaced.
+//
+// Because entries persisted with Merge are only combined when the store is loaded, the operator must be registered
+// before creating a store whose file contains entries that use it. Otherwise, New panics with ErrUnknownMergeOperator.
+func RegisterMergeOperator(name string, fn MergeFunc) {
+	mergeOperatorsMux.Lock()
+	mergeOperators[name] = fn
+	mergeOperatorsMux.Unlock()
+}
+
+// getMergeOperator returns the merge operator registered under a name
+func getMergeOperator(name string) (MergeFunc, error) {
+	mergeOperatorsMux.RLock()
+	fn, exists := mergeOperators[name]
+	mergeOperatorsMux.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMergeOperator, name)
+	}
+	return fn, nil
+}
+
+// Merge atomically combines the value of a key with an operand using the merge operator registered under the name
+// passed as parameter. If the key has an expiration, it is preserved.
+//
+// Unlike Put or Compute, only the operand is persisted, so repeatedly merging small operands into a large value
+// doesn't require rewriting the entire value every time. The operands are combined when the store is loaded, and
+// collapsed into a single entry when the store is consolidated.
+func (store *GDStore) Merge(key, mergeOperator string, operand []byte) error {
+	if _, err := getMergeOperator(mergeOperator); err != nil {
+		return err
+	}
+	defer store.runQueuedCallbacks()
+	unlock := store.lockKeys(key)
+	defer unlock()
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	var entry *Entry
+	if store.exists(key) {
+		_ = store.merge(key, mergeOperator, operand)
+		entry = newEntry(ActionMerge, key, operand)
+		entry.MergeOperator = mergeOperator
+	} else {
+		if value, exists := store.data[key]; exists {
+			store.remove(key)
+			store.expireEntries(map[string][]byte{key: value})
+		}
+		_ = store.merge(key, mergeOperator, operand)
+		// If the key didn't exist, the result doesn't depend on a previous value, so it is persisted as a regular SET.
+		// This also prevents the operand from being merged with an expired value when the store is loaded.
+		entry = newEntry(ActionPut, key, store.data[key])
+	}
+	if err := store.appendEntryToFile(entry); err != nil {
+		return err
+	}
+	return store.evict()
+}
+
+// Append atomically appends data to the value of a key. If the key doesn't exist, it is created.
+//
+// Only the appended data is persisted. See Merge for more details.
+func (store *GDStore) Append(key string, data []byte) error {
+	return store.Merge(key, MergeOperatorAppend, data)
+}
+
+// merge combines the value of a key with an operand in memory while preserving the key's expiration.
+// Must be called while holding store.mux
+func (store *GDStore) merge(key, mergeOperator string, operand []byte) error {
+	fn, err := getMergeOperator(mergeOperator)
+	if err != nil {
+		return err
+	}
+	existingValue := store.data[key]
+	expiry, slidingTTL := store.expiries[key], store.slidingTTLs[key]
+	store.set(key, fn(existingValue, operand), 0)
+	store.setExpiration(key, expiry, slidingTTL)
+	return nil
+}
@@ -0,0 +1,89 @@
+package gdstore
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestGDStore_Append(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	for i := 0; i < 3; i++ {
+		if err := store.Append("events", []byte(strconv.Itoa(i)+";")); err != nil {
+			t.Fatalf("[%s] Unexpected error: %s", t.Name(), err.Error())
+		}
+	}
+	checkValueForKey(t, store, "events", []byte("0;1;2;"))
+	store.Close()
+	// Only the appended data should be persisted
+	content, _ := os.ReadFile(TestStoreFile)
+	if count := bytes.Count(content, []byte(string(ActionMerge)+",")); count != 2 {
+		t.Errorf("[%s] Expected 2 MRG entries, got %d instead:\n%s", t.Name(), count, content)
+	}
+	store = New(TestStoreFile)
+	checkValueForKey(t, store, "events", []byte("0;1;2;"))
+	store.Close()
+	// The store should've been consolidated into a single SET
+	content, _ = os.ReadFile(TestStoreFile)
+	if bytes.Contains(content, []byte(string(ActionMerge)+",")) {
+		t.Errorf("[%s] Expected no MRG entries after consolidation, got:\n%s", t.Name(), content)
+	}
+}
+
+func TestGDStore_AppendPreservesExpiration(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutWithTTL("events", []byte("a"), 50*time.Millisecond)
+	_ = store.Append("events", []byte("b"))
+	if _, ok := store.TTL("events"); !ok {
+		t.Errorf("[%s] Expected the expiration to be preserved", t.Name())
+	}
+	time.Sleep(60 * time.Millisecond)
+	checkKeyNotExists(t, store, "events")
+	// Appending to an expired key should start from an empty value
+	_ = store.Append("events", []byte("c"))
+	checkValueForKey(t, store, "events", []byte("c"))
+	store.Close()
+	store = New(TestStoreFile)
+	checkValueForKey(t, store, "events", []byte("c"))
+	store.Close()
+}
+
+func TestGDStore_MergeWithCustomOperator(t *testing.T) {
+	RegisterMergeOperator("test-max", func(existingValue, operand []byte) []byte {
+		if bytes.Compare(operand, existingValue) > 0 {
+			return operand
+		}
+		return existingValue
+	})
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.Merge("max", "test-max", []byte("3"))
+	_ = store.Merge("max", "test-max", []byte("7"))
+	_ = store.Merge("max", "test-max", []byte("5"))
+	checkValueForKey(t, store, "max", []byte("7"))
+	if err := store.Merge("max", "does-not-exist", []byte("9")); !errors.Is(err, ErrUnknownMergeOperator) {
+		t.Errorf("[%s] Expected ErrUnknownMergeOperator, got %v instead", t.Name(), err)
+	}
+	store.Close()
+	store = New(TestStoreFile)
+	checkValueForKey(t, store, "max", []byte("7"))
+	store.Close()
+}
+
+func TestGDStore_LoadWithUnknownMergeOperator(t *testing.T) {
+	defer deleteTestStoreFile()
+	entry := newEntry(ActionMerge, "key", []byte("value"))
+	entry.MergeOperator = "does-not-exist"
+	_ = os.WriteFile(TestStoreFile, entry.toLine(), 0644)
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("[%s] Expected New to panic because the merge operator isn't registered", t.Name())
+		}
+	}()
+	New(TestStoreFile)
+}
@@ -0,0 +1,197 @@
+package gdstore
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Cursor iterates over the keys of a store in lexicographic order.
+//
+// A cursor doesn't hold any lock between calls, so it always reflects the current content of the store:
+// every call positions the cursor relative to the key it is currently on, even if that key has since been deleted.
+type Cursor struct {
+	store *GDStore
+	key   string
+	valid bool
+}
+
+// WithOrderedIndex sets whether GDStore should maintain an ordered index of its keys.
+//
+// The ordered index allows Scan, ScanPrefix and Cursor to be executed without having to sort every key in
+// the store. Without it, these functions still work, but they have to copy and sort all keys on every call.
+//
+// Defaults to false
+func (store *GDStore) WithOrderedIndex(enabled bool) *GDStore {
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	if !enabled {
+		store.orderedIndex = nil
+	} else if store.orderedIndex == nil {
+		store.orderedIndex = newSkipList()
+		for key := range store.data {
+			store.orderedIndex.insert(key)
+		}
+	}
+	return store
+}
+
+// Scan returns the keys greater than or equal to start and lower than end, in lexicographic order.
+//
+// If end is empty, there is no upper bound. If limit is greater than 0, at most limit keys are returned.
+func (store *GDStore) Scan(start, end string, limit int) []string {
+	store.mux.RLock()
+	defer store.mux.RUnlock()
+	var keys []string
+	store.ascend(start, func(key string) bool {
+		if end != "" && key >= end {
+			return false
+		}
+		keys = append(keys, key)
+		return limit <= 0 || len(keys) < limit
+	})
+	return keys
+}
+
+// ScanPrefix returns the keys that start with prefix, in lexicographic order.
+//
+// If limit is greater than 0, at most limit keys are returned.
+func (store *GDStore) ScanPrefix(prefix string, limit int) []string {
+	store.mux.RLock()
+	defer store.mux.RUnlock()
+	return store.keysWithPrefix(prefix, limit)
+}
+
+// Cursor returns a new Cursor, which can be used to iterate over the keys of the store in both directions
+func (store *GDStore) Cursor() *Cursor {
+	return &Cursor{store: store}
+}
+
+// First moves the cursor to the lowest key and returns it
+func (cursor *Cursor) First() (string, bool) {
+	return cursor.Seek("")
+}
+
+// Last moves the cursor to the highest key and returns it
+func (cursor *Cursor) Last() (string, bool) {
+	cursor.store.mux.RLock()
+	defer cursor.store.mux.RUnlock()
+	return cursor.moveTo(cursor.store.descend(""))
+}
+
+// Seek moves the cursor to the first key greater than or equal to the key passed as parameter and returns it
+func (cursor *Cursor) Seek(key string) (string, bool) {
+	cursor.store.mux.RLock()
+	defer cursor.store.mux.RUnlock()
+	return cursor.moveTo(cursor.store.firstKeyFrom(key))
+}
+
+// Next moves the cursor to the next key and returns it.
+// Returns false if the cursor has reached the end, or if it hasn't been positioned yet.
+func (cursor *Cursor) Next() (string, bool) {
+	if !cursor.valid {
+		return "", false
+	}
+	// The smallest string greater than a key is the key followed by a null byte
+	return cursor.Seek(cursor.key + "\x00")
+}
+
+// Prev moves the cursor to the previous key and returns it.
+// Returns false if the cursor has reached the beginning, or if it hasn't been positioned yet.
+func (cursor *Cursor) Prev() (string, bool) {
+	if !cursor.valid || cursor.key == "" {
+		cursor.valid = false
+		return "", false
+	}
+	cursor.store.mux.RLock()
+	defer cursor.store.mux.RUnlock()
+	return cursor.moveTo(cursor.store.descend(cursor.key))
+}
+
+func (cursor *Cursor) moveTo(key string, ok bool) (string, bool) {
+	cursor.key, cursor.valid = key, ok
+	return key, ok
+}
+
+// firstKeyFrom returns the first key greater than or equal to start. Must be called while holding store.mux
+func (store *GDStore) firstKeyFrom(start string) (firstKey string, ok bool) {
+	store.ascend(start, func(key string) bool {
+		firstKey, ok = key, true
+		return false
+	})
+	return
+}
+
+// keysWithPrefix returns the keys that start with prefix in lexicographic order.
+// Must be called while holding store.mux
+func (store *GDStore) keysWithPrefix(prefix string, limit int) []string {
+	var keys []string
+	store.ascend(prefix, func(key string) bool {
+		if !strings.HasPrefix(key, prefix) {
+			return false
+		}
+		keys = append(keys, key)
+		return limit <= 0 || len(keys) < limit
+	})
+	return keys
+}
+
+// ascend calls fn for every key greater than or equal to start in ascending order, until fn returns false.
+// Expired keys are skipped. Must be called while holding store.mux
+func (store *GDStore) ascend(start string, fn func(key string) bool) {
+	now := time.Now().UnixNano()
+	if store.orderedIndex != nil {
+		for node := store.orderedIndex.seek(start); node != nil; node = node.next[0] {
+			if !store.isExpired(node.key, now) && !fn(node.key) {
+				return
+			}
+		}
+		return
+	}
+	keys := store.sortedKeys(now)
+	for _, key := range keys[sort.SearchStrings(keys, start):] {
+		if !fn(key) {
+			return
+		}
+	}
+}
+
+// descend returns the highest key strictly lower than before. If before is empty, the highest key is returned.
+// Expired keys are skipped. Must be called while holding store.mux
+func (store *GDStore) descend(before string) (string, bool) {
+	now := time.Now().UnixNano()
+	if store.orderedIndex != nil {
+		node := store.orderedIndex.last()
+		if before != "" {
+			node = store.orderedIndex.seekBefore(before)
+		}
+		for ; node != nil; node = node.previous {
+			if !store.isExpired(node.key, now) {
+				return node.key, true
+			}
+		}
+		return "", false
+	}
+	keys := store.sortedKeys(now)
+	index := len(keys)
+	if before != "" {
+		index = sort.SearchStrings(keys, before)
+	}
+	if index == 0 {
+		return "", false
+	}
+	return keys[index-1], true
+}
+
+// sortedKeys returns all keys that haven't expired in lexicographic order.
+// Used when there is no ordered index. Must be called while holding store.mux
+func (store *GDStore) sortedKeys(now int64) []string {
+	keys := make([]string, 0, len(store.data))
+	for key := range store.data {
+		if !store.isExpired(key, now) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
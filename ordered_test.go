@@ -0,0 +1,75 @@
+package gdstore
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGDStore_Scan(t *testing.T) {
+	for _, orderedIndex := range []bool{false, true} {
+		store := New(TestStoreFile).WithOrderedIndex(orderedIndex)
+		_ = store.PutAll(map[string][]byte{"a": nil, "b": nil, "c": nil, "d": nil})
+		_ = store.PutWithTTL("bb", nil, time.Nanosecond)
+		time.Sleep(time.Millisecond)
+		if keys := store.Scan("b", "d", 0); !reflect.DeepEqual(keys, []string{"b", "c"}) {
+			t.Errorf("[%s][orderedIndex=%v] Expected [b c], got %v instead", t.Name(), orderedIndex, keys)
+		}
+		if keys := store.Scan("", "", 3); !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+			t.Errorf("[%s][orderedIndex=%v] Expected [a b c], got %v instead", t.Name(), orderedIndex, keys)
+		}
+		_ = store.Delete("c")
+		if keys := store.Scan("b", "", 0); !reflect.DeepEqual(keys, []string{"b", "d"}) {
+			t.Errorf("[%s][orderedIndex=%v] Expected [b d], got %v instead", t.Name(), orderedIndex, keys)
+		}
+		store.Close()
+		deleteTestStoreFile()
+	}
+}
+
+func TestGDStore_ScanPrefix(t *testing.T) {
+	for _, orderedIndex := range []bool{false, true} {
+		store := New(TestStoreFile).WithOrderedIndex(orderedIndex)
+		_ = store.PutAll(map[string][]byte{"user:2": nil, "user:1": nil, "users": nil, "group:1": nil, "user:3": nil})
+		if keys := store.ScanPrefix("user:", 0); !reflect.DeepEqual(keys, []string{"user:1", "user:2", "user:3"}) {
+			t.Errorf("[%s][orderedIndex=%v] Expected [user:1 user:2 user:3], got %v instead", t.Name(), orderedIndex, keys)
+		}
+		if keys := store.ScanPrefix("user:", 2); !reflect.DeepEqual(keys, []string{"user:1", "user:2"}) {
+			t.Errorf("[%s][orderedIndex=%v] Expected [user:1 user:2], got %v instead", t.Name(), orderedIndex, keys)
+		}
+		store.Close()
+		deleteTestStoreFile()
+	}
+}
+
+func TestCursor(t *testing.T) {
+	for _, orderedIndex := range []bool{false, true} {
+		store := New(TestStoreFile).WithOrderedIndex(orderedIndex)
+		_ = store.PutAll(map[string][]byte{"a": nil, "b": nil, "c": nil})
+		cursor := store.Cursor()
+		var keys []string
+		for key, ok := cursor.First(); ok; key, ok = cursor.Next() {
+			keys = append(keys, key)
+		}
+		if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+			t.Errorf("[%s][orderedIndex=%v] Expected [a b c], got %v instead", t.Name(), orderedIndex, keys)
+		}
+		keys = nil
+		for key, ok := cursor.Last(); ok; key, ok = cursor.Prev() {
+			keys = append(keys, key)
+		}
+		if !reflect.DeepEqual(keys, []string{"c", "b", "a"}) {
+			t.Errorf("[%s][orderedIndex=%v] Expected [c b a], got %v instead", t.Name(), orderedIndex, keys)
+		}
+		if key, ok := cursor.Seek("bb"); !ok || key != "c" {
+			t.Errorf("[%s][orderedIndex=%v] Expected Seek('bb') to return 'c', got '%s' instead", t.Name(), orderedIndex, key)
+		}
+		// Deleting the key the cursor is on shouldn't prevent the cursor from moving
+		_ = store.Delete("c")
+		if key, ok := cursor.Prev(); !ok || key != "b" {
+			t.Errorf("[%s][orderedIndex=%v] Expected Prev() to return 'b', got '%s' instead", t.Name(), orderedIndex, key)
+		}
+		store.Close()
+		deleteTestStoreFile()
+	}
+}
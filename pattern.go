@@ -0,0 +1,175 @@
+package gdstore
+
+import (
+	"errors"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidPattern = errors.New("invalid pattern")
+)
+
+// KeysMatching returns the keys that match a glob pattern, in lexicographic order.
+//
+// The syntax is the same as Redis' KEYS command:
+//   - * matches any sequence of characters, including an empty one
+//   - ? matches exactly one character
+//   - [abc] matches one of the characters between brackets, [^abc] (or [!abc]) matches any other character
+//     and [a-z] matches any character in the range
+//   - \ escapes the next character
+//
+// If the pattern starts with a literal prefix (e.g. user:*) and the store has an ordered index, only the keys
+// with that prefix are looked at.
+func (store *GDStore) KeysMatching(pattern string) ([]string, error) {
+	re, err := compileGlob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	store.mux.RLock()
+	defer store.mux.RUnlock()
+	return store.keysMatching(re), nil
+}
+
+// KeysMatchingRegexp returns the keys that match a regular expression, in lexicographic order.
+//
+// Note that, like regexp.MatchString, the expression matches if any part of the key matches it. If the
+// expression starts with ^ followed by a literal prefix and the store has an ordered index, only the keys with
+// that prefix are looked at.
+func (store *GDStore) KeysMatchingRegexp(expression string) ([]string, error) {
+	re, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	store.mux.RLock()
+	defer store.mux.RUnlock()
+	return store.keysMatching(re), nil
+}
+
+// DeleteMatching deletes all keys that match a glob pattern (see KeysMatching) and returns the number of
+// deleted keys. The deletions are persisted as a single batch.
+func (store *GDStore) DeleteMatching(pattern string) (int, error) {
+	re, err := compileGlob(pattern)
+	if err != nil {
+		return 0, err
+	}
+	defer store.runQueuedCallbacks()
+	unlock := store.lockAllKeys()
+	defer unlock()
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	keys := store.keysMatching(re)
+	entries := make([]*Entry, 0, len(keys))
+	for _, key := range keys {
+		store.remove(key)
+		entries = append(entries, newEntry(ActionDelete, key, nil))
+	}
+	return len(keys), store.appendBatchToFile(entries)
+}
+
+// keysMatching returns the keys that match a regular expression in lexicographic order.
+// Must be called while holding store.mux
+func (store *GDStore) keysMatching(re *regexp.Regexp) []string {
+	var keys []string
+	if prefix := anchoredLiteralPrefix(re); prefix != "" && store.orderedIndex != nil {
+		for _, key := range store.keysWithPrefix(prefix, 0) {
+			if re.MatchString(key) {
+				keys = append(keys, key)
+			}
+		}
+		return keys
+	}
+	now := time.Now().UnixNano()
+	for key := range store.data {
+		if !store.isExpired(key, now) && re.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// anchoredLiteralPrefix returns the literal prefix every key matching the regular expression must start with,
+// or an empty string if there is none
+func anchoredLiteralPrefix(re *regexp.Regexp) string {
+	parsed, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil || parsed.Op != syntax.OpConcat || len(parsed.Sub) < 2 || parsed.Sub[0].Op != syntax.OpBeginText {
+		return ""
+	}
+	// The literal that directly follows ^ is the prefix, unless it's case-insensitive, in which case keys may start
+	// with any variation of it
+	if literal := parsed.Sub[1]; literal.Op == syntax.OpLiteral && literal.Flags&syntax.FoldCase == 0 {
+		return string(literal.Rune)
+	}
+	return ""
+}
+
+// compileGlob converts a glob pattern into a regular expression that matches the entire key
+func compileGlob(pattern string) (*regexp.Regexp, error) {
+	var builder strings.Builder
+	builder.WriteString(`(?s)^`)
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '*':
+			builder.WriteString(`.*`)
+		case '?':
+			builder.WriteString(`.`)
+		case '\\':
+			if i+1 == len(runes) {
+				return nil, ErrInvalidPattern
+			}
+			i++
+			builder.WriteString(regexp.QuoteMeta(string(runes[i])))
+		case '[':
+			end := i + 1
+			if end < len(runes) && (runes[end] == '^' || runes[end] == '!') {
+				end++
+			}
+			// A ] right after the opening bracket (or negation) is part of the set
+			if end < len(runes) && runes[end] == ']' {
+				end++
+			}
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, ErrInvalidPattern
+			}
+			builder.WriteString(globClassToRegexp(runes[i+1 : end]))
+			i = end
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(runes[i])))
+		}
+	}
+	builder.WriteString(`$`)
+	re, err := regexp.Compile(builder.String())
+	if err != nil {
+		return nil, ErrInvalidPattern
+	}
+	return re, nil
+}
+
+// globClassToRegexp converts the content of a glob character class (without the brackets) into a regular
+// expression character class
+func globClassToRegexp(class []rune) string {
+	var builder strings.Builder
+	builder.WriteString("[")
+	if len(class) > 0 && (class[0] == '^' || class[0] == '!') {
+		builder.WriteString("^")
+		class = class[1:]
+	}
+	for _, r := range class {
+		if r == '-' {
+			// Ranges are supported as is
+			builder.WriteRune(r)
+		} else {
+			builder.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	builder.WriteString("]")
+	return builder.String()
+}
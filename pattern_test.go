@@ -0,0 +1,123 @@
+package gdstore
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestCompileGlob(t *testing.T) {
+	scenarios := []struct {
+		pattern       string
+		matching      []string
+		notMatching   []string
+		expectedError bool
+	}{
+		{pattern: "user:*", matching: []string{"user:", "user:1", "user:1:name"}, notMatching: []string{"users", "xuser:1"}},
+		{pattern: "h?llo", matching: []string{"hello", "hallo"}, notMatching: []string{"hllo", "heello"}},
+		{pattern: "h[ae]llo", matching: []string{"hello", "hallo"}, notMatching: []string{"hillo"}},
+		{pattern: "h[^e]llo", matching: []string{"hallo"}, notMatching: []string{"hello"}},
+		{pattern: "h[a-c]llo", matching: []string{"hbllo"}, notMatching: []string{"hdllo"}},
+		{pattern: `h\*llo`, matching: []string{"h*llo"}, notMatching: []string{"hello"}},
+		{pattern: "a.b", matching: []string{"a.b"}, notMatching: []string{"axb"}},
+		{pattern: "h[ae", expectedError: true},
+		{pattern: `h\`, expectedError: true},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.pattern, func(t *testing.T) {
+			re, err := compileGlob(scenario.pattern)
+			if scenario.expectedError {
+				if err != ErrInvalidPattern {
+					t.Errorf("Expected ErrInvalidPattern, got %v instead", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+			for _, key := range scenario.matching {
+				if !re.MatchString(key) {
+					t.Errorf("Expected pattern %s to match %s", scenario.pattern, key)
+				}
+			}
+			for _, key := range scenario.notMatching {
+				if re.MatchString(key) {
+					t.Errorf("Expected pattern %s to not match %s", scenario.pattern, key)
+				}
+			}
+		})
+	}
+}
+
+func TestGDStore_KeysMatching(t *testing.T) {
+	for _, orderedIndex := range []bool{false, true} {
+		store := New(TestStoreFile).WithOrderedIndex(orderedIndex)
+		_ = store.PutAll(map[string][]byte{"user:2": nil, "user:1": nil, "users": nil, "group:1": nil})
+		if keys, _ := store.KeysMatching("user:*"); !reflect.DeepEqual(keys, []string{"user:1", "user:2"}) {
+			t.Errorf("[%s][orderedIndex=%v] Expected [user:1 user:2], got %v instead", t.Name(), orderedIndex, keys)
+		}
+		if keys, _ := store.KeysMatching("*:1"); !reflect.DeepEqual(keys, []string{"group:1", "user:1"}) {
+			t.Errorf("[%s][orderedIndex=%v] Expected [group:1 user:1], got %v instead", t.Name(), orderedIndex, keys)
+		}
+		if keys, _ := store.KeysMatchingRegexp(`^user:\d$`); !reflect.DeepEqual(keys, []string{"user:1", "user:2"}) {
+			t.Errorf("[%s][orderedIndex=%v] Expected [user:1 user:2], got %v instead", t.Name(), orderedIndex, keys)
+		}
+		if keys, _ := store.KeysMatchingRegexp(`:1`); !reflect.DeepEqual(keys, []string{"group:1", "user:1"}) {
+			t.Errorf("[%s][orderedIndex=%v] Expected [group:1 user:1], got %v instead", t.Name(), orderedIndex, keys)
+		}
+		if _, err := store.KeysMatchingRegexp(`(`); err == nil {
+			t.Errorf("[%s][orderedIndex=%v] Expected an error for an invalid regular expression", t.Name(), orderedIndex)
+		}
+		store.Close()
+		deleteTestStoreFile()
+	}
+}
+
+func TestAnchoredLiteralPrefix(t *testing.T) {
+	re, _ := compileGlob("user:*")
+	if prefix := anchoredLiteralPrefix(re); prefix != "user:" {
+		t.Errorf("Expected prefix 'user:', got '%s' instead", prefix)
+	}
+	re, _ = compileGlob("*:1")
+	if prefix := anchoredLiteralPrefix(re); prefix != "" {
+		t.Errorf("Expected no prefix, got '%s' instead", prefix)
+	}
+	for expression, expectedPrefix := range map[string]string{
+		`^user:.*`:     "user:",
+		`^user:[0-9]+`: "user:",
+		`^user:\d+$`:   "user:",
+		`^user`:        "user",
+		`(?i)^user:.*`: "",
+		`(?m)^user:.*`: "",
+		`user:.*`:      "",
+		`^.*:1`:        "",
+	} {
+		if prefix := anchoredLiteralPrefix(regexp.MustCompile(expression)); prefix != expectedPrefix {
+			t.Errorf("Expected prefix '%s' for %s, got '%s' instead", expectedPrefix, expression, prefix)
+		}
+	}
+}
+
+func TestGDStore_DeleteMatching(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutAll(map[string][]byte{"user:1": nil, "user:2": nil, "user:3": nil, "group:1": nil})
+	deleted, err := store.DeleteMatching("user:*")
+	if err != nil || deleted != 3 {
+		t.Errorf("[%s] Expected 3 keys to have been deleted without error, got %d and %v", t.Name(), deleted, err)
+	}
+	if keys := store.Keys(); len(keys) != 1 || keys[0] != "group:1" {
+		t.Errorf("[%s] Expected only group:1 to be left, got %v instead", t.Name(), keys)
+	}
+	// 4 SET + 1 BAT + 3 DEL
+	fileContent := getStoreFileContent(store)
+	if numberOfLines := len(strings.Split(fileContent, "\n")); numberOfLines != 8 {
+		t.Errorf("[%s] Store file should've had 8 lines, but had %d instead", t.Name(), numberOfLines)
+	}
+	store = New(TestStoreFile)
+	if store.Count() != 1 {
+		t.Errorf("[%s] Expected to have 1 entry after reloading the store, got %d instead", t.Name(), store.Count())
+	}
+	store.Close()
+}
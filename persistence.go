@@ -3,7 +3,11 @@ package gdstore
 import (
 	"bufio"
 	"fmt"
+	"math"
 	"os"
+	"sort"
+	"strconv"
+	"time"
 )
 
 // Close closes the store's file if it isn't already closed. Will also flush to buffer if useBuffer is true.
@@ -40,6 +44,22 @@ func (store *GDStore) Flush() error {
 func (store *GDStore) Consolidate() error {
 	store.mux.Lock()
 	defer store.mux.Unlock()
+	unlock, err := store.lockStoreFile()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	// Leases may have been changed by another process using the same file since they were last read
+	if err = store.reloadLeases(); err != nil {
+		return err
+	}
+	return store.consolidate()
+}
+
+// consolidate re-saves only the entries required to re-create the current state of the store.
+// Must be called while holding store.mux and the lock of the store's file
+func (store *GDStore) consolidate() error {
+	store.purgeChanges(time.Now().UnixNano())
 	if !store.persistence {
 		return nil
 	}
@@ -62,15 +82,110 @@ func (store *GDStore) Consolidate() error {
 	// Close store AFTER appending all entries to the new file (hence defer)
 	// to make sure all the data is definitely in the new file
 	defer store.Close()
-	return store.appendEntriesToFile(newBulkEntries(ActionPut, store.data))
+	return store.appendEntriesToFile(store.snapshotEntries())
+}
+
+// lockStoreFile acquires an exclusive lock on the lock file of the store, which is located next to the store's file,
+// and returns a function that releases it. This prevents other processes using the same file from consolidating it
+// or changing a lease at the same time. Does nothing if persistence is disabled.
+//
+// The store's file itself isn't locked, because it's replaced when it's consolidated.
+func (store *GDStore) lockStoreFile() (func(), error) {
+	if !store.persistence {
+		return func() {}, nil
+	}
+	file, err := os.OpenFile(store.FilePath+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err = lockExclusively(file); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("unable to lock %s.lock: %s", store.FilePath, err.Error())
+	}
+	return func() {
+		_ = file.Close()
+	}, nil
+}
+
+// snapshotEntries returns the entries required to re-create the current state of the store.
+// Expired entries are omitted. Must be called while holding store.mux
+//
+// Entries keep the sequence number of the last change made to their key, and if the change feed is enabled,
+// tombstones are persisted as DEL entries so that the change feed survives consolidation.
+func (store *GDStore) snapshotEntries() []*Entry {
+	now := time.Now().UnixNano()
+	entries := make([]*Entry, 0, len(store.data)+len(store.tombstones))
+	for key, value := range store.data {
+		if store.isExpired(key, now) {
+			continue
+		}
+		entry := store.withExpiration(newEntry(ActionPut, key, value))
+		entry.Sequence = store.keySequences[key]
+		entries = append(entries, entry)
+	}
+	for key, tombstone := range store.tombstones {
+		entry := newEntry(ActionDelete, key, nil)
+		entry.Sequence, entry.Timestamp = tombstone.sequence, tombstone.timestamp
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Sequence < entries[j].Sequence
+	})
+	bucketEntries := store.bucketSnapshotEntries()
+	if !store.isChangeFeedEnabled() {
+		return append(entries, bucketEntries...)
+	}
+	// The retention must be restored before the tombstones are loaded
+	header := []*Entry{newEntry(ActionRetention, "", int64Converter.format(int64(store.tombstoneRetention)))}
+	// Bucket entries are assigned new sequence numbers, so a SEQ entry is only necessary if the last sequence number
+	// or the watermark wouldn't otherwise be restored when the store is loaded
+	lastSequence := uint64(0)
+	if len(entries) > 0 {
+		lastSequence = entries[len(entries)-1].Sequence
+	}
+	if store.sequenceWatermark > 0 || (len(bucketEntries) == 0 && store.sequence > lastSequence) {
+		sequence := newEntry(ActionSequence, "", uint64Converter.format(store.sequenceWatermark))
+		sequence.Sequence = store.sequence
+		header = append(header, sequence)
+	}
+	return append(append(header, entries...), bucketEntries...)
 }
 
 // loadFromDisk loads the store from the disk and consolidates the entries, or creates an empty file if there is no file
 func (store *GDStore) loadFromDisk() error {
 	store.data = make(map[string][]byte)
+	store.expiries = make(map[string]int64)
+	store.slidingTTLs = make(map[string]time.Duration)
+	store.persistedExpiries = make(map[string]int64)
+	store.size = 0
+	store.buckets = make(map[string]map[string][]byte)
+	store.sortedSets = make(map[string]*sortedSetIndex)
+	store.queues = make(map[string]*queueIndex)
+	store.sequence, store.sequenceWatermark = 0, 0
+	// The retention is restored from the store's file
+	store.tombstoneRetention = 0
+	store.keySequences = make(map[string]uint64)
+	store.tombstones = make(map[string]tombstone)
+	// Indexes are rebuilt as the entries are loaded
+	if store.orderedIndex != nil {
+		store.orderedIndex = newSkipList()
+	}
+	for _, index := range store.indexes {
+		index.reset()
+	}
+	if store.fullTextIndex != nil {
+		store.fullTextIndex = newFullTextIndex()
+	}
+	store.evictionTracker = nil
+	store.initializeEvictionPolicy()
 	if !store.persistence {
 		return nil
 	}
+	unlock, err := store.lockStoreFile()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	file, err := os.Open(store.FilePath)
 	if err != nil {
 		// Check if the file exists, if it doesn't, then create it and return.
@@ -85,20 +200,127 @@ func (store *GDStore) loadFromDisk() error {
 		}
 	}
 	// File doesn't exist, so we need to read it.
+	err = readEntries(file, store.applyEntry)
+	_ = file.Close()
+	if err != nil {
+		return err
+	}
+	// Entries that have expired while the store wasn't loaded are skipped
+	store.removeExpiredEntries(time.Now().UnixNano())
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	return store.consolidate()
+}
+
+// readEntries reads the entries of a store's file and passes them to apply in the order in which they were written.
+// Lines that cannot be parsed are skipped.
+//
+// Entries that are part of a batch are only passed once every entry of the batch has been read, and are skipped
+// if any of them couldn't be read.
+func readEntries(file *os.File, apply func(entry *Entry) error) error {
 	scanner := bufio.NewScanner(file)
+	// Lines may be longer than the default limit of the scanner, especially when an entire hash is persisted as
+	// a single entry
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), math.MaxInt32)
+	var batch []*Entry
+	remainingEntriesInBatch, isBatchValid := 0, false
 	for scanner.Scan() {
 		entry, err := newEntryFromLine(scanner.Text())
+		if remainingEntriesInBatch > 0 {
+			remainingEntriesInBatch--
+			if err != nil || entry.Action == ActionBatch {
+				isBatchValid = false
+			} else {
+				batch = append(batch, entry)
+			}
+			if remainingEntriesInBatch == 0 && isBatchValid {
+				for _, batchEntry := range batch {
+					if err = apply(batchEntry); err != nil {
+						return err
+					}
+				}
+			}
+			continue
+		}
 		if err != nil {
 			continue
 		}
-		if entry.Action == ActionPut {
-			store.data[entry.Key] = entry.Value
-		} else if entry.Action == ActionDelete {
-			delete(store.data, entry.Key)
+		if entry.Action == ActionBatch {
+			remainingEntriesInBatch, err = strconv.Atoi(string(entry.Value))
+			batch, isBatchValid = nil, err == nil
+			continue
+		}
+		if err = apply(entry); err != nil {
+			return err
 		}
 	}
-	_ = file.Close()
-	return store.Consolidate()
+	return nil
+}
+
+// applyEntry applies an entry read from the store's file to memory
+func (store *GDStore) applyEntry(entry *Entry) (err error) {
+	if entry.Sequence == 0 && hasSequence(entry) {
+		// Entries written before sequence numbers existed are numbered in the order in which they're read
+		store.sequence++
+		entry.Sequence = store.sequence
+	} else if entry.Sequence > store.sequence {
+		store.sequence = entry.Sequence
+	}
+	if entry.Bucket != "" {
+		store.applyBucketEntry(entry)
+		return nil
+	}
+	switch entry.Action {
+	case ActionPut:
+		store.set(entry.Key, entry.Value, 0)
+		store.setExpiration(entry.Key, entry.Expiry, entry.SlidingTTL)
+	case ActionDelete:
+		store.remove(entry.Key)
+	case ActionExpire:
+		if _, exists := store.data[entry.Key]; exists {
+			store.setExpiration(entry.Key, entry.Expiry, entry.SlidingTTL)
+		}
+	case ActionClear:
+		store.removeAll()
+	case ActionRename:
+		if _, exists := store.data[entry.Key]; exists {
+			store.rename(entry.Key, string(entry.Value))
+		}
+	case ActionMerge:
+		err = store.merge(entry.Key, entry.MergeOperator, entry.Value)
+	case ActionSequence:
+		if watermark, err := uint64Converter.parse(entry.Value); err == nil && watermark > store.sequenceWatermark {
+			store.sequenceWatermark = watermark
+		}
+	case ActionRetention:
+		if retention, err := int64Converter.parse(entry.Value); err == nil && retention >= 0 {
+			store.tombstoneRetention = time.Duration(retention)
+			if retention == 0 {
+				store.tombstones = make(map[string]tombstone)
+			}
+		}
+	}
+	store.trackChange(entry)
+	return
+}
+
+// hasSequence returns whether an entry is assigned a sequence number when it's written. BAT and RET entries don't
+// change the content of the store, so they aren't.
+func hasSequence(entry *Entry) bool {
+	return entry.Action != ActionBatch && entry.Action != ActionRetention
+}
+
+// appendBatchToFile appends a list of entries to the store's file as a batch.
+//
+// A batch is preceded by a BAT entry containing the number of entries in the batch, and when the store is loaded,
+// the entries of a batch are only applied if every single one of them could be read. In other words, if the
+// application crashes while a batch is being written, either all entries of the batch are applied, or none are.
+func (store *GDStore) appendBatchToFile(entries []*Entry) error {
+	if len(entries) <= 1 {
+		return store.appendEntriesToFile(entries)
+	}
+	header := newEntry(ActionBatch, "", []byte(strconv.Itoa(len(entries))))
+	return store.appendEntriesToFile(append([]*Entry{header}, entries...))
 }
 
 // appendEntryToFile appends an entry to the store's file
@@ -106,8 +328,20 @@ func (store *GDStore) appendEntryToFile(entry *Entry) error {
 	return store.appendEntriesToFile([]*Entry{entry})
 }
 
-// appendEntriesToFile appends a list of entries to the store's file
+// appendEntriesToFile assigns a sequence number to a list of entries and appends them to the store's file.
+// Must be called while holding store.mux
 func (store *GDStore) appendEntriesToFile(entries []*Entry) (err error) {
+	now := time.Now().UnixNano()
+	for _, entry := range entries {
+		if entry.Sequence == 0 && hasSequence(entry) {
+			store.sequence++
+			entry.Sequence = store.sequence
+		}
+		if entry.Timestamp == 0 && store.isTombstone(entry) {
+			entry.Timestamp = now
+		}
+		store.trackChange(entry)
+	}
 	if !store.persistence {
 		return
 	}
@@ -118,15 +352,19 @@ func (store *GDStore) appendEntriesToFile(entries []*Entry) (err error) {
 		}
 		store.writer = bufio.NewWriter(store.file)
 	}
+	// All entries are written at once rather than one by one, which is faster and makes it less likely
+	// for only some of the entries to be persisted if the application crashes
+	var lines []byte
 	for _, entry := range entries {
-		if store.useBuffer {
-			_, err = store.writer.Write(entry.toLine())
-		} else {
-			_, err = store.file.Write(entry.toLine())
-		}
-		if err != nil {
-			return
-		}
+		lines = append(lines, entry.toLine()...)
+	}
+	if len(lines) == 0 {
+		return
+	}
+	if store.useBuffer {
+		_, err = store.writer.Write(lines)
+	} else {
+		_, err = store.file.Write(lines)
 	}
 	return
 }
@@ -76,3 +76,19 @@ func getStoreBackupFileContent(store *GDStore) string {
 	raw, _ := ioutil.ReadFile(fmt.Sprintf("%s.bak", store.FilePath))
 	return strings.TrimSpace(string(raw))
 }
+
+func TestGDStore_loadFromDiskWithIncompleteBatch(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.Put("key", []byte("value"))
+	_ = store.appendBatchToFile([]*Entry{newEntry(ActionDelete, "key", nil), newEntry(ActionPut, "other", nil)})
+	store.Close()
+	// Simulate a crash in the middle of the batch by removing the last line
+	raw, _ := ioutil.ReadFile(TestStoreFile)
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	_ = ioutil.WriteFile(TestStoreFile, []byte(strings.Join(lines[:len(lines)-1], "\n")+"\n"), 0644)
+	store = New(TestStoreFile)
+	checkValueForKey(t, store, "key", []byte("value"))
+	checkKeyNotExists(t, store, "other")
+	store.Close()
+}
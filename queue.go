@@ -0,0 +1,259 @@
+package gdstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	queueBucketPrefix   = internalBucketPrefix + "queue:"
+	queueJobKeyPrefix   = "job:"
+	queueLeaseKeyPrefix = "lease:"
+	queueSequenceKey    = "seq"
+)
+
+var (
+	ErrQueueEmpty  = errors.New("no job available in queue")
+	ErrJobNotFound = errors.New("job not found")
+)
+
+// Queue is a persistent FIFO queue with acknowledgement.
+//
+// A job that has been dequeued stays in the queue, but is invisible to other calls to Dequeue until its visibility
+// timeout expires, at which point it is delivered again unless it has been acknowledged with Ack. This means that a
+// job that was being processed when the application crashed or restarted will eventually be redelivered; in other
+// words, jobs are delivered at least once.
+//
+// The jobs, the leases of the jobs being processed and the last sequence number are persisted as regular entries in
+// an internal bucket of the store, which is not returned by Buckets and cannot be accessed through Bucket.
+type Queue struct {
+	store  *GDStore
+	bucket string
+}
+
+// Job is a job that has been dequeued from a Queue
+type Job struct {
+	// ID is the sequence number of the job, which is unique within the queue
+	ID uint64
+
+	// Payload is the data passed to Enqueue
+	Payload []byte
+
+	// Deadline is the time at which the job will be delivered again if it hasn't been acknowledged
+	Deadline time.Time
+}
+
+// Queue returns the queue with the given name. Queues don't need to be created before being used.
+func (store *GDStore) Queue(name string) *Queue {
+	return &Queue{store: store, bucket: queueBucketPrefix + name}
+}
+
+// Enqueue adds a job at the end of the queue and returns its ID
+func (queue *Queue) Enqueue(payload []byte) (uint64, error) {
+	queue.store.mux.Lock()
+	defer queue.store.mux.Unlock()
+	id := uint64(1)
+	if sequence, exists := queue.store.buckets[queue.bucket][queueSequenceKey]; exists {
+		lastID, err := uint64Converter.parse(sequence)
+		if err != nil {
+			return 0, &DecodeError{Key: queueSequenceKey, Err: err}
+		}
+		id = lastID + 1
+	}
+	jobKey := queueJobKey(id)
+	queue.store.setInBucket(queue.bucket, queueSequenceKey, uint64Converter.format(id))
+	queue.store.setInBucket(queue.bucket, jobKey, payload)
+	return id, queue.store.appendBatchToFile([]*Entry{
+		queue.newEntry(ActionPut, queueSequenceKey, uint64Converter.format(id)),
+		queue.newEntry(ActionPut, jobKey, payload),
+	})
+}
+
+// Dequeue returns the oldest job that is neither acknowledged nor being processed, and hides it from other calls to
+// Dequeue for the duration of the visibility timeout. If there is no such job, ErrQueueEmpty is returned.
+//
+// Once the job has been processed, it must be acknowledged with Ack, otherwise, it will be delivered again.
+func (queue *Queue) Dequeue(visibilityTimeout time.Duration) (*Job, error) {
+	queue.store.mux.Lock()
+	defer queue.store.mux.Unlock()
+	now := time.Now()
+	index := queue.store.queues[queue.bucket]
+	if index == nil {
+		return nil, ErrQueueEmpty
+	}
+	index.releaseExpiredLeases(now.UnixNano())
+	node := index.pendingJobs.first()
+	if node == nil {
+		return nil, ErrQueueEmpty
+	}
+	jobKey := node.key
+	id, err := uint64Converter.parse([]byte(strings.TrimPrefix(jobKey, queueJobKeyPrefix)))
+	if err != nil {
+		return nil, &DecodeError{Key: jobKey, Err: err}
+	}
+	job := &Job{
+		ID:       id,
+		Payload:  queue.store.buckets[queue.bucket][jobKey],
+		Deadline: now.Add(visibilityTimeout),
+	}
+	lease := int64Converter.format(job.Deadline.UnixNano())
+	queue.store.setInBucket(queue.bucket, queueLeaseKey(jobKey), lease)
+	return job, queue.store.appendEntryToFile(queue.newEntry(ActionPut, queueLeaseKey(jobKey), lease))
+}
+
+// Ack acknowledges a job, which removes it from the queue.
+//
+// Note that if the visibility timeout of the job has expired, the job may have been delivered again, in which case
+// it is acknowledged regardless.
+func (queue *Queue) Ack(id uint64) error {
+	queue.store.mux.Lock()
+	defer queue.store.mux.Unlock()
+	jobKey := queueJobKey(id)
+	if _, exists := queue.store.buckets[queue.bucket][jobKey]; !exists {
+		return ErrJobNotFound
+	}
+	queue.store.removeFromBucket(queue.bucket, jobKey)
+	queue.store.removeFromBucket(queue.bucket, queueLeaseKey(jobKey))
+	return queue.store.appendBatchToFile([]*Entry{
+		queue.newEntry(ActionDelete, jobKey, nil),
+		queue.newEntry(ActionDelete, queueLeaseKey(jobKey), nil),
+	})
+}
+
+// Nack gives up on a job, which makes it immediately available to Dequeue again
+func (queue *Queue) Nack(id uint64) error {
+	queue.store.mux.Lock()
+	defer queue.store.mux.Unlock()
+	jobKey := queueJobKey(id)
+	if _, exists := queue.store.buckets[queue.bucket][jobKey]; !exists {
+		return ErrJobNotFound
+	}
+	queue.store.removeFromBucket(queue.bucket, queueLeaseKey(jobKey))
+	return queue.store.appendEntryToFile(queue.newEntry(ActionDelete, queueLeaseKey(jobKey), nil))
+}
+
+// Len returns the number of jobs that haven't been acknowledged, including the jobs that are being processed
+func (queue *Queue) Len() int {
+	queue.store.mux.RLock()
+	defer queue.store.mux.RUnlock()
+	if index := queue.store.queues[queue.bucket]; index != nil {
+		return len(index.jobs)
+	}
+	return 0
+}
+
+// newEntry creates a new entry that belongs to the queue's bucket
+func (queue *Queue) newEntry(action Action, key string, value []byte) *Entry {
+	entry := newEntry(action, key, value)
+	entry.Bucket = queue.bucket
+	return entry
+}
+
+// queueJobKey returns the key of the job with the given ID
+func queueJobKey(id uint64) string {
+	return fmt.Sprintf("%s%020d", queueJobKeyPrefix, id)
+}
+
+// queueLeaseKey returns the key of the lease of the job with the given key
+func queueLeaseKey(jobKey string) string {
+	return queueLeaseKeyPrefix + strings.TrimPrefix(jobKey, queueJobKeyPrefix)
+}
+
+// queueIndex keeps track of the jobs of a queue, so that Dequeue doesn't need to go through every job
+type queueIndex struct {
+	jobs map[string]bool
+
+	// pendingJobs contains the keys of the jobs that aren't being processed. Keys are zero-padded, so the
+	// lexicographic order is the same as the order in which jobs were enqueued.
+	pendingJobs *skipList
+
+	// deadlines contains the deadline of the lease of every job that is being processed, indexed by job key
+	deadlines map[string]int64
+
+	// leases contains a key for each job that is being processed made of the deadline of its lease, encoded so that
+	// its lexicographic order is the same as its numeric order, followed by the job key
+	leases *skipList
+}
+
+// releaseExpiredLeases makes the jobs whose lease has expired pending again
+func (index *queueIndex) releaseExpiredLeases(now int64) {
+	for node := index.leases.first(); node != nil; node = index.leases.first() {
+		jobKey := node.key[8:]
+		if index.deadlines[jobKey] > now {
+			break
+		}
+		index.removeLease(jobKey)
+	}
+}
+
+// removeLease removes the lease of a job, which makes the job pending again if it hasn't been acknowledged
+func (index *queueIndex) removeLease(jobKey string) {
+	if deadline, leased := index.deadlines[jobKey]; leased {
+		index.leases.remove(queueLeaseIndexKey(jobKey, deadline))
+		delete(index.deadlines, jobKey)
+	}
+	if index.jobs[jobKey] {
+		index.pendingJobs.insert(jobKey)
+	}
+}
+
+// queueLeaseIndexKey returns the key of the lease of a job in queueIndex.leases
+func queueLeaseIndexKey(jobKey string, deadline int64) string {
+	var key [8]byte
+	// The sign bit is flipped so that negative deadlines are ordered before positive ones
+	binary.BigEndian.PutUint64(key[:], uint64(deadline)^(1<<63))
+	return string(key[:]) + jobKey
+}
+
+// indexQueueKey updates the index of a queue after a key has been set in its bucket.
+// Does nothing if the bucket doesn't belong to a queue. Must be called while holding store.mux
+func (store *GDStore) indexQueueKey(bucket, key string, value []byte) {
+	if !strings.HasPrefix(bucket, queueBucketPrefix) {
+		return
+	}
+	index := store.queues[bucket]
+	if index == nil {
+		index = &queueIndex{jobs: make(map[string]bool), pendingJobs: newSkipList(), deadlines: make(map[string]int64), leases: newSkipList()}
+		store.queues[bucket] = index
+	}
+	switch {
+	case strings.HasPrefix(key, queueJobKeyPrefix):
+		index.jobs[key] = true
+		if _, leased := index.deadlines[key]; !leased {
+			index.pendingJobs.insert(key)
+		}
+	case strings.HasPrefix(key, queueLeaseKeyPrefix):
+		jobKey := queueJobKeyPrefix + strings.TrimPrefix(key, queueLeaseKeyPrefix)
+		index.removeLease(jobKey)
+		deadline, err := int64Converter.parse(value)
+		if err != nil {
+			// Like a lease that has expired, a lease that cannot be parsed doesn't prevent the job from being delivered
+			return
+		}
+		index.pendingJobs.remove(jobKey)
+		index.deadlines[jobKey] = deadline
+		index.leases.insert(queueLeaseIndexKey(jobKey, deadline))
+	}
+}
+
+// unindexQueueKey updates the index of a queue after a key has been removed from its bucket.
+// Must be called while holding store.mux
+func (store *GDStore) unindexQueueKey(bucket, key string) {
+	index := store.queues[bucket]
+	if index == nil {
+		return
+	}
+	switch {
+	case strings.HasPrefix(key, queueJobKeyPrefix):
+		delete(index.jobs, key)
+		index.pendingJobs.remove(key)
+	case strings.HasPrefix(key, queueLeaseKeyPrefix):
+		index.removeLease(queueJobKeyPrefix + strings.TrimPrefix(key, queueLeaseKeyPrefix))
+	}
+	if len(index.jobs) == 0 && len(index.deadlines) == 0 {
+		delete(store.queues, bucket)
+	}
+}
@@ -0,0 +1,152 @@
+package gdstore
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueue(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	queue := store.Queue("jobs")
+	for _, payload := range []string{"a", "b", "c"} {
+		if _, err := queue.Enqueue([]byte(payload)); err != nil {
+			t.Fatalf("[%s] Unexpected error: %s", t.Name(), err.Error())
+		}
+	}
+	if queue.Len() != 3 {
+		t.Errorf("[%s] Expected length to be 3, got %d instead", t.Name(), queue.Len())
+	}
+	job, err := queue.Dequeue(time.Minute)
+	if err != nil || job.ID != 1 || string(job.Payload) != "a" {
+		t.Fatalf("[%s] Expected job 1 (a), got %+v and %v instead", t.Name(), job, err)
+	}
+	secondJob, _ := queue.Dequeue(time.Minute)
+	if secondJob == nil || string(secondJob.Payload) != "b" {
+		t.Fatalf("[%s] Expected job b, got %+v instead", t.Name(), secondJob)
+	}
+	if err := queue.Ack(job.ID); err != nil {
+		t.Errorf("[%s] Unexpected error: %s", t.Name(), err.Error())
+	}
+	if err := queue.Ack(job.ID); err != ErrJobNotFound {
+		t.Errorf("[%s] Expected ErrJobNotFound, got %v instead", t.Name(), err)
+	}
+	if err := queue.Nack(secondJob.ID); err != nil {
+		t.Errorf("[%s] Unexpected error: %s", t.Name(), err.Error())
+	}
+	// b was nacked, so it should be delivered again before c
+	if job, _ = queue.Dequeue(time.Minute); job == nil || string(job.Payload) != "b" {
+		t.Errorf("[%s] Expected job b to be delivered again, got %+v instead", t.Name(), job)
+	}
+	if job, _ = queue.Dequeue(time.Minute); job == nil || string(job.Payload) != "c" {
+		t.Errorf("[%s] Expected job c, got %+v instead", t.Name(), job)
+	}
+	if _, err := queue.Dequeue(time.Minute); err != ErrQueueEmpty {
+		t.Errorf("[%s] Expected ErrQueueEmpty, got %v instead", t.Name(), err)
+	}
+	if queue.Len() != 2 {
+		t.Errorf("[%s] Expected length to be 2, got %d instead", t.Name(), queue.Len())
+	}
+	store.Close()
+}
+
+func TestQueue_RedeliveryAfterVisibilityTimeout(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	queue := store.Queue("jobs")
+	_, _ = queue.Enqueue([]byte("a"))
+	job, _ := queue.Dequeue(50 * time.Millisecond)
+	if _, err := queue.Dequeue(time.Minute); err != ErrQueueEmpty {
+		t.Errorf("[%s] Expected ErrQueueEmpty, got %v instead", t.Name(), err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if redeliveredJob, err := queue.Dequeue(time.Minute); err != nil || redeliveredJob.ID != job.ID {
+		t.Errorf("[%s] Expected job %d to be delivered again, got %+v and %v instead", t.Name(), job.ID, redeliveredJob, err)
+	}
+	store.Close()
+}
+
+func TestQueue_Persistence(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	queue := store.Queue("jobs")
+	_, _ = queue.Enqueue([]byte("a"))
+	_, _ = queue.Enqueue([]byte("b"))
+	_, _ = queue.Dequeue(50 * time.Millisecond)
+	job, _ := queue.Dequeue(50 * time.Millisecond)
+	_ = queue.Ack(job.ID)
+	store.Close()
+	time.Sleep(60 * time.Millisecond)
+	store = New(TestStoreFile)
+	queue = store.Queue("jobs")
+	if queue.Len() != 1 {
+		t.Errorf("[%s] Expected length to be 1, got %d instead", t.Name(), queue.Len())
+	}
+	// a was never acknowledged, so it should be delivered again
+	if job, err := queue.Dequeue(time.Minute); err != nil || job.ID != 1 || string(job.Payload) != "a" {
+		t.Errorf("[%s] Expected job 1 (a) to be delivered again, got %+v and %v instead", t.Name(), job, err)
+	}
+	// IDs should never be reused
+	if id, _ := queue.Enqueue([]byte("c")); id != 3 {
+		t.Errorf("[%s] Expected id to be 3, got %d instead", t.Name(), id)
+	}
+	store.Close()
+}
+
+func TestQueue_PersistenceWithJobBeingProcessed(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	queue := store.Queue("jobs")
+	for _, payload := range []string{"a", "b", "c"} {
+		_, _ = queue.Enqueue([]byte(payload))
+	}
+	_, _ = queue.Dequeue(time.Minute)
+	store.Close()
+	// The leases and the jobs may be loaded in any order once the store has been consolidated
+	for i := 0; i < 5; i++ {
+		store = New(TestStoreFile)
+		queue = store.Queue("jobs")
+		if job, err := queue.Dequeue(time.Millisecond); err != nil || job.ID != 2 {
+			t.Errorf("[%s] Expected job 2 to be delivered, because job 1 is still being processed, got %+v and %v instead", t.Name(), job, err)
+		}
+		_ = queue.Nack(2)
+		store.Close()
+	}
+}
+
+func TestQueue_Concurrency(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	queue := store.Queue("jobs")
+	for i := 0; i < 100; i++ {
+		_, _ = queue.Enqueue([]byte("job"))
+	}
+	delivered := make(map[uint64]bool)
+	var mux sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				job, err := queue.Dequeue(time.Minute)
+				if err != nil {
+					return
+				}
+				mux.Lock()
+				if delivered[job.ID] {
+					t.Errorf("[%s] Job %d was delivered twice", t.Name(), job.ID)
+				}
+				delivered[job.ID] = true
+				mux.Unlock()
+				_ = queue.Ack(job.ID)
+			}
+		}()
+	}
+	wg.Wait()
+	if len(delivered) != 100 || queue.Len() != 0 {
+		t.Errorf("[%s] Expected 100 jobs to be delivered and the queue to be empty, got %d and %d instead", t.Name(), len(delivered), queue.Len())
+	}
+	store.Close()
+}
@@ -0,0 +1,72 @@
+package gdstore
+
+import (
+	"errors"
+)
+
+var (
+	ErrKeyAlreadyExists = errors.New("key already exists")
+)
+
+// Rename atomically moves the value of a key to another key. If the old key has an expiration, it is moved as well.
+//
+// The rename is persisted as a single REN entry, which means that if the application crashes, either the old key
+// exists or the new key exists, but never both or neither.
+//
+// Returns ErrKeyNotFound if the old key doesn't exist, and ErrKeyAlreadyExists if the new key already exists and
+// overwrite is false.
+func (store *GDStore) Rename(oldKey, newKey string, overwrite bool) error {
+	defer store.runQueuedCallbacks()
+	unlock := store.lockKeys(oldKey, newKey)
+	defer unlock()
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	if !store.exists(oldKey) {
+		return ErrKeyNotFound
+	}
+	if oldKey == newKey {
+		return nil
+	}
+	if !overwrite && store.exists(newKey) {
+		return ErrKeyAlreadyExists
+	}
+	store.rename(oldKey, newKey)
+	return store.appendEntryToFile(newEntry(ActionRename, oldKey, []byte(newKey)))
+}
+
+// Copy atomically copies the value of a key to another key. If the source key has an expiration, it is copied as well.
+//
+// The copy is persisted as a single SET entry.
+//
+// Returns ErrKeyNotFound if the source key doesn't exist, and ErrKeyAlreadyExists if the destination key already
+// exists and overwrite is false.
+func (store *GDStore) Copy(source, destination string, overwrite bool) error {
+	defer store.runQueuedCallbacks()
+	unlock := store.lockKeys(source, destination)
+	defer unlock()
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	if !store.exists(source) {
+		return ErrKeyNotFound
+	}
+	if source == destination {
+		return nil
+	}
+	if !overwrite && store.exists(destination) {
+		return ErrKeyAlreadyExists
+	}
+	store.set(destination, store.data[source], 0)
+	store.setExpiration(destination, store.expiries[source], store.slidingTTLs[source])
+	if err := store.appendEntryToFile(store.withExpiration(newEntry(ActionPut, destination, store.data[source]))); err != nil {
+		return err
+	}
+	return store.evict()
+}
+
+// rename moves the value and the expiration of a key to another key in memory. Must be called while holding store.mux
+func (store *GDStore) rename(oldKey, newKey string) {
+	value, expiry, slidingTTL := store.data[oldKey], store.expiries[oldKey], store.slidingTTLs[oldKey]
+	store.remove(oldKey)
+	store.set(newKey, value, 0)
+	store.setExpiration(newKey, expiry, slidingTTL)
+}
@@ -0,0 +1,68 @@
+package gdstore
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGDStore_Rename(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutWithTTL("pending:1", []byte("job"), time.Hour)
+	_ = store.Put("done:2", []byte("other job"))
+	if err := store.Rename("pending:1", "done:1", false); err != nil {
+		t.Fatalf("[%s] Unexpected error: %s", t.Name(), err.Error())
+	}
+	checkKeyNotExists(t, store, "pending:1")
+	checkValueForKey(t, store, "done:1", []byte("job"))
+	if _, ok := store.TTL("done:1"); !ok {
+		t.Errorf("[%s] Expected the TTL to have been moved to the new key", t.Name())
+	}
+	if err := store.Rename("pending:1", "done:1", false); err != ErrKeyNotFound {
+		t.Errorf("[%s] Expected ErrKeyNotFound, got %v instead", t.Name(), err)
+	}
+	if err := store.Rename("done:1", "done:2", false); err != ErrKeyAlreadyExists {
+		t.Errorf("[%s] Expected ErrKeyAlreadyExists, got %v instead", t.Name(), err)
+	}
+	if err := store.Rename("done:1", "done:2", true); err != nil {
+		t.Errorf("[%s] Unexpected error: %s", t.Name(), err.Error())
+	}
+	checkValueForKey(t, store, "done:2", []byte("job"))
+	// 2 SET + 2 REN
+	fileContent := getStoreFileContent(store)
+	if numberOfLines := len(strings.Split(fileContent, "\n")); numberOfLines != 4 {
+		t.Errorf("[%s] Store file should've had 4 lines, but had %d instead", t.Name(), numberOfLines)
+	}
+	store = New(TestStoreFile)
+	if keys := store.Keys(); len(keys) != 1 || keys[0] != "done:2" {
+		t.Errorf("[%s] Expected only key done:2 to exist after reloading the store, got %v instead", t.Name(), keys)
+	}
+	if _, ok := store.TTL("done:2"); !ok {
+		t.Errorf("[%s] Expected the TTL to have been persisted", t.Name())
+	}
+	store.Close()
+}
+
+func TestGDStore_Copy(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.Put("source", []byte("value"))
+	_ = store.Put("existing", []byte("existing"))
+	if err := store.Copy("source", "destination", false); err != nil {
+		t.Fatalf("[%s] Unexpected error: %s", t.Name(), err.Error())
+	}
+	checkValueForKey(t, store, "source", []byte("value"))
+	checkValueForKey(t, store, "destination", []byte("value"))
+	if err := store.Copy("source", "existing", false); err != ErrKeyAlreadyExists {
+		t.Errorf("[%s] Expected ErrKeyAlreadyExists, got %v instead", t.Name(), err)
+	}
+	checkValueForKey(t, store, "existing", []byte("existing"))
+	if err := store.Copy("missing", "destination", true); err != ErrKeyNotFound {
+		t.Errorf("[%s] Expected ErrKeyNotFound, got %v instead", t.Name(), err)
+	}
+	store.Close()
+	store = New(TestStoreFile)
+	checkValueForKey(t, store, "destination", []byte("value"))
+	store.Close()
+}
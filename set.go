@@ -0,0 +1,132 @@
+package gdstore
+
+import (
+	"sort"
+)
+
+const (
+	setBucketPrefix = internalBucketPrefix + "set:"
+)
+
+// Set is a persistent set of unique strings.
+//
+// Each member is persisted as a separate entry in an internal bucket of the store, so adding or removing a member
+// doesn't require re-writing the entire set.
+type Set struct {
+	store  *GDStore
+	bucket string
+}
+
+// Set returns the set with the given name. Sets don't need to be created before being used.
+func (store *GDStore) Set(name string) *Set {
+	return &Set{store: store, bucket: setBucketPrefix + name}
+}
+
+// Add adds one or more members to the set. Members that are already in the set are ignored.
+func (set *Set) Add(members ...string) error {
+	set.store.mux.Lock()
+	defer set.store.mux.Unlock()
+	var entries []*Entry
+	for _, member := range members {
+		if _, exists := set.store.buckets[set.bucket][member]; exists {
+			continue
+		}
+		set.store.setInBucket(set.bucket, member, nil)
+		entries = append(entries, set.newEntry(ActionPut, member))
+	}
+	return set.store.appendBatchToFile(entries)
+}
+
+// Remove removes one or more members from the set. Members that aren't in the set are ignored.
+func (set *Set) Remove(members ...string) error {
+	set.store.mux.Lock()
+	defer set.store.mux.Unlock()
+	var entries []*Entry
+	for _, member := range members {
+		if _, exists := set.store.buckets[set.bucket][member]; !exists {
+			continue
+		}
+		set.store.removeFromBucket(set.bucket, member)
+		entries = append(entries, set.newEntry(ActionDelete, member))
+	}
+	return set.store.appendBatchToFile(entries)
+}
+
+// IsMember returns whether a member is in the set
+func (set *Set) IsMember(member string) bool {
+	set.store.mux.RLock()
+	_, exists := set.store.buckets[set.bucket][member]
+	set.store.mux.RUnlock()
+	return exists
+}
+
+// Members returns all members of the set in lexicographic order
+func (set *Set) Members() []string {
+	set.store.mux.RLock()
+	members := make([]string, 0, len(set.store.buckets[set.bucket]))
+	for member := range set.store.buckets[set.bucket] {
+		members = append(members, member)
+	}
+	set.store.mux.RUnlock()
+	sort.Strings(members)
+	return members
+}
+
+// Len returns the number of members in the set
+func (set *Set) Len() int {
+	set.store.mux.RLock()
+	defer set.store.mux.RUnlock()
+	return len(set.store.buckets[set.bucket])
+}
+
+// Union returns the members that are in the set or in any of the other sets, in lexicographic order.
+// The other sets must belong to the same store.
+func (set *Set) Union(others ...*Set) []string {
+	set.store.mux.RLock()
+	members := make(map[string]bool)
+	for _, s := range append([]*Set{set}, others...) {
+		for member := range set.store.buckets[s.bucket] {
+			members[member] = true
+		}
+	}
+	set.store.mux.RUnlock()
+	return membersInOrder(members)
+}
+
+// Intersect returns the members that are in the set and in every one of the other sets, in lexicographic order.
+// The other sets must belong to the same store.
+func (set *Set) Intersect(others ...*Set) []string {
+	set.store.mux.RLock()
+	members := make(map[string]bool)
+	for member := range set.store.buckets[set.bucket] {
+		isInEverySet := true
+		for _, other := range others {
+			if _, exists := set.store.buckets[other.bucket][member]; !exists {
+				isInEverySet = false
+				break
+			}
+		}
+		if isInEverySet {
+			members[member] = true
+		}
+	}
+	set.store.mux.RUnlock()
+	return membersInOrder(members)
+}
+
+// newEntry creates a new entry that belongs to the set's bucket
+func (set *Set) newEntry(action Action, member string) *Entry {
+	entry := newEntry(action, member, nil)
+	entry.Bucket = set.bucket
+	return entry
+}
+
+// membersInOrder returns the keys of a map of members in lexicographic order
+func membersInOrder(members map[string]bool) []string {
+	sortedMembers := make([]string, 0, len(members))
+	for member := range members {
+		sortedMembers = append(sortedMembers, member)
+	}
+	sort.Strings(sortedMembers)
+	return sortedMembers
+}
@@ -0,0 +1,55 @@
+package gdstore
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	set := store.Set("tags")
+	_ = set.Add("go", "rust", "go")
+	_ = set.Add("c")
+	_ = set.Remove("rust", "does-not-exist")
+	if !set.IsMember("go") || set.IsMember("rust") {
+		t.Errorf("[%s] Expected go to be a member and rust to not be a member", t.Name())
+	}
+	if members := set.Members(); !reflect.DeepEqual(members, []string{"c", "go"}) {
+		t.Errorf("[%s] Expected [c go], got %v instead", t.Name(), members)
+	}
+	if set.Len() != 2 {
+		t.Errorf("[%s] Expected length to be 2, got %d instead", t.Name(), set.Len())
+	}
+	if store.Count() != 0 {
+		t.Errorf("[%s] Expected members to not be counted as entries of the store", t.Name())
+	}
+	store.Close()
+	store = New(TestStoreFile)
+	if members := store.Set("tags").Members(); !reflect.DeepEqual(members, []string{"c", "go"}) {
+		t.Errorf("[%s] Expected [c go] after re-loading the store, got %v instead", t.Name(), members)
+	}
+	store.Close()
+}
+
+func TestSet_UnionAndIntersect(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	a, b, c := store.Set("a"), store.Set("b"), store.Set("c")
+	_ = a.Add("1", "2", "3")
+	_ = b.Add("2", "3", "4")
+	_ = c.Add("3", "5")
+	if members := a.Union(b, c); !reflect.DeepEqual(members, []string{"1", "2", "3", "4", "5"}) {
+		t.Errorf("[%s] Expected [1 2 3 4 5], got %v instead", t.Name(), members)
+	}
+	if members := a.Intersect(b); !reflect.DeepEqual(members, []string{"2", "3"}) {
+		t.Errorf("[%s] Expected [2 3], got %v instead", t.Name(), members)
+	}
+	if members := a.Intersect(b, c); !reflect.DeepEqual(members, []string{"3"}) {
+		t.Errorf("[%s] Expected [3], got %v instead", t.Name(), members)
+	}
+	if members := a.Intersect(store.Set("empty")); len(members) != 0 {
+		t.Errorf("[%s] Expected no members, got %v instead", t.Name(), members)
+	}
+	store.Close()
+}
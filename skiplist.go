@@ -0,0 +1,145 @@
+package gdstore
+
+import (
+	"math/rand"
+)
+
+const (
+	skipListMaxLevel    = 32
+	skipListProbability = 0.25
+)
+
+// skipList is an ordered set of keys used as the ordered index of a store.
+//
+// It is not goroutine-safe; callers are expected to hold store.mux.
+type skipList struct {
+	head   *skipListNode
+	tail   *skipListNode
+	level  int
+	length int
+	random *rand.Rand
+}
+
+type skipListNode struct {
+	key      string
+	next     []*skipListNode
+	previous *skipListNode
+}
+
+func newSkipList() *skipList {
+	return &skipList{
+		head:   &skipListNode{next: make([]*skipListNode, skipListMaxLevel)},
+		level:  1,
+		random: rand.New(rand.NewSource(rand.Int63())),
+	}
+}
+
+// insert adds a key to the list. Does nothing if the key is already in the list.
+func (list *skipList) insert(key string) {
+	var update [skipListMaxLevel]*skipListNode
+	node := list.head
+	for i := list.level - 1; i >= 0; i-- {
+		for node.next[i] != nil && node.next[i].key < key {
+			node = node.next[i]
+		}
+		update[i] = node
+	}
+	if node.next[0] != nil && node.next[0].key == key {
+		return
+	}
+	level := list.randomLevel()
+	if level > list.level {
+		for i := list.level; i < level; i++ {
+			update[i] = list.head
+		}
+		list.level = level
+	}
+	newNode := &skipListNode{key: key, next: make([]*skipListNode, level)}
+	for i := 0; i < level; i++ {
+		newNode.next[i] = update[i].next[i]
+		update[i].next[i] = newNode
+	}
+	if update[0] != list.head {
+		newNode.previous = update[0]
+	}
+	if newNode.next[0] != nil {
+		newNode.next[0].previous = newNode
+	} else {
+		list.tail = newNode
+	}
+	list.length++
+}
+
+// remove deletes a key from the list. Does nothing if the key isn't in the list.
+func (list *skipList) remove(key string) {
+	var update [skipListMaxLevel]*skipListNode
+	node := list.head
+	for i := list.level - 1; i >= 0; i-- {
+		for node.next[i] != nil && node.next[i].key < key {
+			node = node.next[i]
+		}
+		update[i] = node
+	}
+	node = node.next[0]
+	if node == nil || node.key != key {
+		return
+	}
+	for i := 0; i < list.level; i++ {
+		if update[i].next[i] != node {
+			break
+		}
+		update[i].next[i] = node.next[i]
+	}
+	if node.next[0] != nil {
+		node.next[0].previous = node.previous
+	} else {
+		list.tail = node.previous
+	}
+	for list.level > 1 && list.head.next[list.level-1] == nil {
+		list.level--
+	}
+	list.length--
+}
+
+// seek returns the first node whose key is greater than or equal to the key passed as parameter
+func (list *skipList) seek(key string) *skipListNode {
+	node := list.head
+	for i := list.level - 1; i >= 0; i-- {
+		for node.next[i] != nil && node.next[i].key < key {
+			node = node.next[i]
+		}
+	}
+	return node.next[0]
+}
+
+// seekBefore returns the last node whose key is strictly lower than the key passed as parameter
+func (list *skipList) seekBefore(key string) *skipListNode {
+	node := list.head
+	for i := list.level - 1; i >= 0; i-- {
+		for node.next[i] != nil && node.next[i].key < key {
+			node = node.next[i]
+		}
+	}
+	if node == list.head {
+		return nil
+	}
+	return node
+}
+
+// first returns the node with the lowest key
+func (list *skipList) first() *skipListNode {
+	return list.head.next[0]
+}
+
+// last returns the node with the highest key
+func (list *skipList) last() *skipListNode {
+	return list.tail
+}
+
+func (list *skipList) randomLevel() int {
+	level := 1
+	for level < skipListMaxLevel && list.random.Float64() < skipListProbability {
+		level++
+	}
+	return level
+}
@@ -0,0 +1,69 @@
+package gdstore
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestSkipList(t *testing.T) {
+	list := newSkipList()
+	expected := make(map[string]bool)
+	for i := 0; i < 2000; i++ {
+		key := fmt.Sprintf("%d", rand.Intn(500))
+		if rand.Intn(3) == 0 {
+			list.remove(key)
+			delete(expected, key)
+		} else {
+			list.insert(key)
+			expected[key] = true
+		}
+	}
+	var expectedKeys []string
+	for key := range expected {
+		expectedKeys = append(expectedKeys, key)
+	}
+	sort.Strings(expectedKeys)
+	if list.length != len(expectedKeys) {
+		t.Fatalf("Expected skip list to have %d keys, got %d instead", len(expectedKeys), list.length)
+	}
+	i := 0
+	for node := list.first(); node != nil; node = node.next[0] {
+		if node.key != expectedKeys[i] {
+			t.Fatalf("Expected key at index %d to be %s, got %s instead", i, expectedKeys[i], node.key)
+		}
+		i++
+	}
+	i = len(expectedKeys) - 1
+	for node := list.last(); node != nil; node = node.previous {
+		if node.key != expectedKeys[i] {
+			t.Fatalf("Expected key at index %d to be %s, got %s instead", i, expectedKeys[i], node.key)
+		}
+		i--
+	}
+	if i != -1 {
+		t.Errorf("Expected to iterate over all keys in reverse, stopped at index %d", i)
+	}
+}
+
+func TestSkipList_Seek(t *testing.T) {
+	list := newSkipList()
+	list.insert("b")
+	list.insert("d")
+	if node := list.seek("a"); node == nil || node.key != "b" {
+		t.Error("Expected seek('a') to return 'b'")
+	}
+	if node := list.seek("c"); node == nil || node.key != "d" {
+		t.Error("Expected seek('c') to return 'd'")
+	}
+	if node := list.seek("e"); node != nil {
+		t.Error("Expected seek('e') to return nil")
+	}
+	if node := list.seekBefore("d"); node == nil || node.key != "b" {
+		t.Error("Expected seekBefore('d') to return 'b'")
+	}
+	if node := list.seekBefore("b"); node != nil {
+		t.Error("Expected seekBefore('b') to return nil")
+	}
+}
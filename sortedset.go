@@ -0,0 +1,190 @@
+package gdstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"strings"
+)
+
+const (
+	sortedSetBucketPrefix = internalBucketPrefix + "zset:"
+)
+
+var (
+	ErrInvalidScore = errors.New("score must be a number")
+)
+
+// SortedSet is a persistent set of unique strings, each of which has a score by which members are ordered.
+// Members that have the same score are ordered lexicographically.
+//
+// Each member is persisted as a separate entry in an internal bucket of the store, with its score as value,
+// so updating a member doesn't require re-writing the entire set. Members are also kept in an in-memory index
+// ordered by score, which is rebuilt as the entries are loaded.
+type SortedSet struct {
+	store  *GDStore
+	bucket string
+}
+
+// ScoredMember is a member of a SortedSet along with its score
+type ScoredMember struct {
+	Member string
+	Score  float64
+}
+
+// SortedSet returns the sorted set with the given name. Sorted sets don't need to be created before being used.
+func (store *GDStore) SortedSet(name string) *SortedSet {
+	return &SortedSet{store: store, bucket: sortedSetBucketPrefix + name}
+}
+
+// Add adds a member to the sorted set, or updates its score if it's already in the sorted set
+func (sortedSet *SortedSet) Add(member string, score float64) error {
+	if math.IsNaN(score) {
+		return ErrInvalidScore
+	}
+	sortedSet.store.mux.Lock()
+	defer sortedSet.store.mux.Unlock()
+	value := float64Converter.format(score)
+	sortedSet.store.setInBucket(sortedSet.bucket, member, value)
+	return sortedSet.store.appendEntryToFile(sortedSet.newEntry(ActionPut, member, value))
+}
+
+// Remove removes one or more members from the sorted set. Members that aren't in the sorted set are ignored.
+func (sortedSet *SortedSet) Remove(members ...string) error {
+	sortedSet.store.mux.Lock()
+	defer sortedSet.store.mux.Unlock()
+	var entries []*Entry
+	for _, member := range members {
+		if _, exists := sortedSet.store.buckets[sortedSet.bucket][member]; !exists {
+			continue
+		}
+		sortedSet.store.removeFromBucket(sortedSet.bucket, member)
+		entries = append(entries, sortedSet.newEntry(ActionDelete, member, nil))
+	}
+	return sortedSet.store.appendBatchToFile(entries)
+}
+
+// Score returns the score of a member as well as a bool that indicates whether the member is in the sorted set
+func (sortedSet *SortedSet) Score(member string) (score float64, ok bool) {
+	sortedSet.store.mux.RLock()
+	defer sortedSet.store.mux.RUnlock()
+	if index := sortedSet.store.sortedSets[sortedSet.bucket]; index != nil {
+		score, ok = index.scores[member]
+	}
+	return
+}
+
+// Rank returns the position of a member in the sorted set, starting from 0 for the member with the lowest score,
+// as well as a bool that indicates whether the member is in the sorted set
+func (sortedSet *SortedSet) Rank(member string) (int, bool) {
+	sortedSet.store.mux.RLock()
+	defer sortedSet.store.mux.RUnlock()
+	index := sortedSet.store.sortedSets[sortedSet.bucket]
+	if index == nil {
+		return 0, false
+	}
+	score, ok := index.scores[member]
+	if !ok {
+		return 0, false
+	}
+	rank := 0
+	target := sortedSetIndexKey(member, score)
+	for node := index.members.first(); node != nil && node.key < target; node = node.next[0] {
+		rank++
+	}
+	return rank, true
+}
+
+// RangeByScore returns the members whose score is between min and max (inclusively), ordered by score.
+// If limit is greater than 0, at most limit members are returned.
+func (sortedSet *SortedSet) RangeByScore(min, max float64, limit int) []ScoredMember {
+	sortedSet.store.mux.RLock()
+	defer sortedSet.store.mux.RUnlock()
+	var members []ScoredMember
+	index := sortedSet.store.sortedSets[sortedSet.bucket]
+	if index == nil {
+		return members
+	}
+	for node := index.members.seek(sortedSetIndexKey("", min)); node != nil && (limit <= 0 || len(members) < limit); node = node.next[0] {
+		member := node.key[8:]
+		score := index.scores[member]
+		if score > max {
+			break
+		}
+		members = append(members, ScoredMember{Member: member, Score: score})
+	}
+	return members
+}
+
+// Len returns the number of members in the sorted set
+func (sortedSet *SortedSet) Len() int {
+	sortedSet.store.mux.RLock()
+	defer sortedSet.store.mux.RUnlock()
+	return len(sortedSet.store.buckets[sortedSet.bucket])
+}
+
+// newEntry creates a new entry that belongs to the sorted set's bucket
+func (sortedSet *SortedSet) newEntry(action Action, member string, value []byte) *Entry {
+	entry := newEntry(action, member, value)
+	entry.Bucket = sortedSet.bucket
+	return entry
+}
+
+// sortedSetIndex contains the members of a sorted set ordered by score
+type sortedSetIndex struct {
+	scores map[string]float64
+
+	// members contains a key for each member made of the member's score, encoded so that its lexicographic order
+	// is the same as its numeric order, followed by the member itself
+	members *skipList
+}
+
+// sortedSetIndexKey returns the key of a member in sortedSetIndex.members
+func sortedSetIndexKey(member string, score float64) string {
+	bits := math.Float64bits(score)
+	if bits&(1<<63) != 0 {
+		// Negative numbers are ordered in reverse, so all bits are flipped
+		bits = ^bits
+	} else {
+		bits |= 1 << 63
+	}
+	var key [8]byte
+	binary.BigEndian.PutUint64(key[:], bits)
+	return string(key[:]) + member
+}
+
+// indexSortedSetMember updates the index of a sorted set after a member has been set in its bucket.
+// Does nothing if the bucket doesn't belong to a sorted set. Must be called while holding store.mux
+func (store *GDStore) indexSortedSetMember(bucket, member string, value []byte) {
+	if !strings.HasPrefix(bucket, sortedSetBucketPrefix) {
+		return
+	}
+	store.unindexSortedSetMember(bucket, member)
+	score, err := float64Converter.parse(value)
+	if err != nil || math.IsNaN(score) {
+		return
+	}
+	index := store.sortedSets[bucket]
+	if index == nil {
+		index = &sortedSetIndex{scores: make(map[string]float64), members: newSkipList()}
+		store.sortedSets[bucket] = index
+	}
+	index.scores[member] = score
+	index.members.insert(sortedSetIndexKey(member, score))
+}
+
+// unindexSortedSetMember updates the index of a sorted set after a member has been removed from its bucket.
+// Must be called while holding store.mux
+func (store *GDStore) unindexSortedSetMember(bucket, member string) {
+	index := store.sortedSets[bucket]
+	if index == nil {
+		return
+	}
+	if score, exists := index.scores[member]; exists {
+		index.members.remove(sortedSetIndexKey(member, score))
+		delete(index.scores, member)
+	}
+	if len(index.scores) == 0 {
+		delete(store.sortedSets, bucket)
+	}
+}
@@ -0,0 +1,84 @@
+package gdstore
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSortedSet(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	leaderboard := store.SortedSet("leaderboard")
+	_ = leaderboard.Add("john", 100)
+	_ = leaderboard.Add("jane", 250.5)
+	_ = leaderboard.Add("bob", -10)
+	_ = leaderboard.Add("alice", 100)
+	_ = leaderboard.Add("john", 300)
+	if err := leaderboard.Add("nan", math.NaN()); err != ErrInvalidScore {
+		t.Errorf("[%s] Expected ErrInvalidScore, got %v instead", t.Name(), err)
+	}
+	expectedMembers := []ScoredMember{{"bob", -10}, {"alice", 100}, {"jane", 250.5}, {"john", 300}}
+	if members := leaderboard.RangeByScore(math.Inf(-1), math.Inf(1), 0); !reflect.DeepEqual(members, expectedMembers) {
+		t.Errorf("[%s] Expected %v, got %v instead", t.Name(), expectedMembers, members)
+	}
+	if members := leaderboard.RangeByScore(0, 250.5, 0); !reflect.DeepEqual(members, expectedMembers[1:3]) {
+		t.Errorf("[%s] Expected %v, got %v instead", t.Name(), expectedMembers[1:3], members)
+	}
+	if members := leaderboard.RangeByScore(0, 1000, 1); !reflect.DeepEqual(members, expectedMembers[1:2]) {
+		t.Errorf("[%s] Expected %v, got %v instead", t.Name(), expectedMembers[1:2], members)
+	}
+	if rank, ok := leaderboard.Rank("jane"); !ok || rank != 2 {
+		t.Errorf("[%s] Expected jane to have rank 2, got %d instead", t.Name(), rank)
+	}
+	if _, ok := leaderboard.Rank("does-not-exist"); ok {
+		t.Errorf("[%s] Expected does-not-exist to not have a rank", t.Name())
+	}
+	if score, ok := leaderboard.Score("john"); !ok || score != 300 {
+		t.Errorf("[%s] Expected john to have score 300, got %f instead", t.Name(), score)
+	}
+	_ = leaderboard.Remove("bob")
+	if rank, _ := leaderboard.Rank("jane"); rank != 1 {
+		t.Errorf("[%s] Expected jane to have rank 1, got %d instead", t.Name(), rank)
+	}
+	store.Close()
+	store = New(TestStoreFile)
+	leaderboard = store.SortedSet("leaderboard")
+	if members := leaderboard.RangeByScore(math.Inf(-1), math.Inf(1), 0); !reflect.DeepEqual(members, expectedMembers[1:]) {
+		t.Errorf("[%s] Expected %v after re-loading the store, got %v instead", t.Name(), expectedMembers[1:], members)
+	}
+	if leaderboard.Len() != 3 {
+		t.Errorf("[%s] Expected length to be 3, got %d instead", t.Name(), leaderboard.Len())
+	}
+	store.Close()
+}
+
+func TestSortedSet_Drop(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	leaderboard := store.SortedSet("leaderboard")
+	_ = leaderboard.Add("john", 1)
+	store.mux.Lock()
+	store.removeBucket(sortedSetBucketPrefix + "leaderboard")
+	store.mux.Unlock()
+	if members := leaderboard.RangeByScore(math.Inf(-1), math.Inf(1), 0); len(members) != 0 {
+		t.Errorf("[%s] Expected no members, got %v instead", t.Name(), members)
+	}
+	store.Close()
+}
+
+func TestSortedSet_WithBucketWithSameName(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.SortedSet("leaderboard").Add("john", 1)
+	bucket := store.Bucket("zset:leaderboard")
+	_ = bucket.Put("jane", []byte("2"))
+	_ = bucket.Drop()
+	if members := store.SortedSet("leaderboard").RangeByScore(math.Inf(-1), math.Inf(1), 0); len(members) != 1 || members[0].Member != "john" {
+		t.Errorf("[%s] Expected the bucket to not affect the sorted set, got %v instead", t.Name(), members)
+	}
+	if len(store.sortedSets) != 1 {
+		t.Errorf("[%s] Expected only the sorted set to be indexed, got %d indexes instead", t.Name(), len(store.sortedSets))
+	}
+	store.Close()
+}
@@ -0,0 +1,160 @@
+package gdstore
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PathSeparator is the separator between the segments of a path used by ListChildren, DeleteTree, CopyTree and
+// MoveTree
+const PathSeparator = "/"
+
+var (
+	ErrInvalidTreeDestination = errors.New("destination must not be empty or inside the source tree")
+)
+
+// ListChildren returns the paths of the direct children of a path, in lexicographic order.
+//
+// For instance, if the store contains the keys a/b/c, a/b/d and a/e, the children of a are a/b and a/e.
+// The children of the empty path are the top-level segments (e.g. a).
+//
+// If the store has an ordered index, the descendants of each child are skipped rather than scanned.
+func (store *GDStore) ListChildren(path string) []string {
+	store.mux.RLock()
+	defer store.mux.RUnlock()
+	prefix := treePrefix(path)
+	seen := make(map[string]bool)
+	var children []string
+	addChild := func(key string) string {
+		child := childPath(prefix, key)
+		if !seen[child] {
+			seen[child] = true
+			children = append(children, child)
+		}
+		return child
+	}
+	if store.orderedIndex != nil {
+		for key, ok := store.firstKeyFrom(prefix); ok && strings.HasPrefix(key, prefix); {
+			if child := addChild(key); child != key {
+				// The first key after all descendants of the child is the child followed by the character that
+				// comes right after the separator
+				key, ok = store.firstKeyFrom(child + string(PathSeparator[0]+1))
+			} else {
+				key, ok = store.firstKeyFrom(key + "\x00")
+			}
+		}
+	} else {
+		for _, key := range store.keysWithPrefix(prefix, 0) {
+			addChild(key)
+		}
+	}
+	// A child that has descendants may come after a sibling that starts with the same characters (e.g. a/b/c comes
+	// after a/b-c because - comes before /), so the children must be sorted again
+	sort.Strings(children)
+	return children
+}
+
+// DeleteTree deletes a path as well as all of its descendants, and returns the number of deleted keys.
+// The deletions are persisted as a single batch.
+func (store *GDStore) DeleteTree(path string) (int, error) {
+	defer store.runQueuedCallbacks()
+	unlock := store.lockAllKeys()
+	defer unlock()
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	keys := store.treeKeys(path)
+	entries := make([]*Entry, 0, len(keys))
+	for _, key := range keys {
+		store.remove(key)
+		entries = append(entries, newEntry(ActionDelete, key, nil))
+	}
+	return len(keys), store.appendBatchToFile(entries)
+}
+
+// CopyTree copies a path as well as all of its descendants to another path, overwriting existing keys.
+// Expirations are copied as well. The copies are persisted as a single batch.
+//
+// For instance, copying a to b with the keys a, a/x and a/y/z creates b, b/x and b/y/z.
+//
+// Returns ErrInvalidTreeDestination if the destination is the source or one of its descendants, or if it's the empty
+// path, since the source itself cannot be copied to the root.
+func (store *GDStore) CopyTree(source, destination string) error {
+	return store.copyTree(source, destination, false)
+}
+
+// MoveTree moves a path as well as all of its descendants to another path, overwriting existing keys.
+// The move is persisted as a single batch.
+//
+// Returns ErrInvalidTreeDestination if the destination is the source or one of its descendants, or if it's the empty
+// path, since the source itself cannot be moved to the root.
+func (store *GDStore) MoveTree(source, destination string) error {
+	return store.copyTree(source, destination, true)
+}
+
+func (store *GDStore) copyTree(source, destination string, deleteSource bool) error {
+	if destination == "" || destination == source || strings.HasPrefix(destination, treePrefix(source)) {
+		return ErrInvalidTreeDestination
+	}
+	defer store.runQueuedCallbacks()
+	unlock := store.lockAllKeys()
+	defer unlock()
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	keys := store.treeKeys(source)
+	// The destination may be an ancestor of the source, in which case some destination keys are also source keys, so
+	// the source is read in its entirety before anything is written
+	values, expiries, slidingTTLs := make([][]byte, len(keys)), make([]int64, len(keys)), make([]time.Duration, len(keys))
+	for i, key := range keys {
+		values[i], expiries[i], slidingTTLs[i] = store.data[key], store.expiries[key], store.slidingTTLs[key]
+	}
+	entries := make([]*Entry, 0, len(keys)*2)
+	copiedKeys := make(map[string]bool, len(keys))
+	for i, key := range keys {
+		newKey := destination + strings.TrimPrefix(key, source)
+		store.set(newKey, values[i], 0)
+		store.setExpiration(newKey, expiries[i], slidingTTLs[i])
+		entries = append(entries, store.withExpiration(newEntry(ActionPut, newKey, values[i])))
+		copiedKeys[newKey] = true
+	}
+	if deleteSource {
+		for _, key := range keys {
+			if copiedKeys[key] {
+				// The key has been overwritten by the move, so it must not be deleted
+				continue
+			}
+			store.remove(key)
+			entries = append(entries, newEntry(ActionDelete, key, nil))
+		}
+	}
+	if err := store.appendBatchToFile(entries); err != nil {
+		return err
+	}
+	return store.evict()
+}
+
+// treeKeys returns a path and all of its descendants in lexicographic order. Must be called while holding store.mux
+func (store *GDStore) treeKeys(path string) []string {
+	var keys []string
+	if path != "" && store.exists(path) {
+		keys = append(keys, path)
+	}
+	return append(keys, store.keysWithPrefix(treePrefix(path), 0)...)
+}
+
+// treePrefix returns the prefix shared by all descendants of a path
+func treePrefix(path string) string {
+	if path == "" {
+		return ""
+	}
+	return path + PathSeparator
+}
+
+// childPath returns the path of the direct child of the path with the given prefix that a key descends from
+func childPath(prefix, key string) string {
+	if index := strings.Index(key[len(prefix):], PathSeparator); index != -1 {
+		return key[:len(prefix)+index]
+	}
+	return key
+}
@@ -0,0 +1,126 @@
+package gdstore
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGDStore_ListChildren(t *testing.T) {
+	for _, orderedIndex := range []bool{false, true} {
+		store := New(TestStoreFile).WithOrderedIndex(orderedIndex)
+		_ = store.PutAll(map[string][]byte{"a/b/c": nil, "a/b/d": nil, "a/b": nil, "a/b-c": nil, "a/e": nil, "f": nil, "ab": nil})
+		if children := store.ListChildren("a"); !reflect.DeepEqual(children, []string{"a/b", "a/b-c", "a/e"}) {
+			t.Errorf("[%s][orderedIndex=%v] Expected [a/b a/b-c a/e], got %v instead", t.Name(), orderedIndex, children)
+		}
+		if children := store.ListChildren(""); !reflect.DeepEqual(children, []string{"a", "ab", "f"}) {
+			t.Errorf("[%s][orderedIndex=%v] Expected [a ab f], got %v instead", t.Name(), orderedIndex, children)
+		}
+		if children := store.ListChildren("a/e"); len(children) != 0 {
+			t.Errorf("[%s][orderedIndex=%v] Expected no children, got %v instead", t.Name(), orderedIndex, children)
+		}
+		store.Close()
+		deleteTestStoreFile()
+	}
+}
+
+func TestGDStore_DeleteTree(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutAll(map[string][]byte{"a": nil, "a/b": nil, "a/b/c": nil, "ab": nil})
+	if deleted, err := store.DeleteTree("a"); err != nil || deleted != 3 {
+		t.Errorf("[%s] Expected 3 keys to have been deleted without error, got %d and %v", t.Name(), deleted, err)
+	}
+	if keys := store.Keys(); len(keys) != 1 || keys[0] != "ab" {
+		t.Errorf("[%s] Expected only key ab to be left, got %v instead", t.Name(), keys)
+	}
+	store.Close()
+	store = New(TestStoreFile)
+	if store.Count() != 1 {
+		t.Errorf("[%s] Expected to have 1 entry after reloading the store, got %d instead", t.Name(), store.Count())
+	}
+	store.Close()
+}
+
+func TestGDStore_CopyTree(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutAll(map[string][]byte{"a": []byte("1"), "a/b": []byte("2"), "a/b/c": []byte("3"), "ab": []byte("4")})
+	if err := store.CopyTree("a", "x/y"); err != nil {
+		t.Fatalf("[%s] Unexpected error: %s", t.Name(), err.Error())
+	}
+	checkValueForKey(t, store, "x/y", []byte("1"))
+	checkValueForKey(t, store, "x/y/b", []byte("2"))
+	checkValueForKey(t, store, "x/y/b/c", []byte("3"))
+	checkValueForKey(t, store, "a/b/c", []byte("3"))
+	checkKeyNotExists(t, store, "x/yb")
+	if err := store.CopyTree("a", "a/z"); err != ErrInvalidTreeDestination {
+		t.Errorf("[%s] Expected ErrInvalidTreeDestination, got %v instead", t.Name(), err)
+	}
+	// 4 SET + 1 BAT + 3 SET
+	if numberOfLines := len(strings.Split(getStoreFileContent(store), "\n")); numberOfLines != 8 {
+		t.Errorf("[%s] Store file should've had 8 lines, but had %d instead", t.Name(), numberOfLines)
+	}
+	store.Close()
+}
+
+func TestGDStore_MoveTree(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutAll(map[string][]byte{"a/b": []byte("1"), "a/b/c": []byte("2"), "a/d": []byte("3")})
+	if err := store.MoveTree("a/b", "a"); err != nil {
+		t.Fatalf("[%s] Unexpected error: %s", t.Name(), err.Error())
+	}
+	checkValueForKey(t, store, "a", []byte("1"))
+	checkValueForKey(t, store, "a/c", []byte("2"))
+	checkValueForKey(t, store, "a/d", []byte("3"))
+	checkKeyNotExists(t, store, "a/b")
+	checkKeyNotExists(t, store, "a/b/c")
+	store.Close()
+	store = New(TestStoreFile)
+	if store.Count() != 3 {
+		t.Errorf("[%s] Expected to have 3 entries after reloading the store, got %d instead", t.Name(), store.Count())
+	}
+	store.Close()
+}
+
+func TestGDStore_MoveTreeToAncestorOverwritingSource(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutAll(map[string][]byte{"a/b": []byte("ab"), "a/b/b": []byte("abb"), "a/b/b/c": []byte("abbc"), "a/b/c": []byte("abc")})
+	if err := store.MoveTree("a/b", "a"); err != nil {
+		t.Fatalf("[%s] Unexpected error: %s", t.Name(), err.Error())
+	}
+	checkValueForKey(t, store, "a", []byte("ab"))
+	checkValueForKey(t, store, "a/b", []byte("abb"))
+	checkValueForKey(t, store, "a/b/c", []byte("abbc"))
+	checkValueForKey(t, store, "a/c", []byte("abc"))
+	checkKeyNotExists(t, store, "a/b/b")
+	checkKeyNotExists(t, store, "a/b/b/c")
+	store.Close()
+	store = New(TestStoreFile)
+	checkValueForKey(t, store, "a/b", []byte("abb"))
+	checkValueForKey(t, store, "a/b/c", []byte("abbc"))
+	if store.Count() != 4 {
+		t.Errorf("[%s] Expected to have 4 entries after reloading the store, got %d instead", t.Name(), store.Count())
+	}
+	store.Close()
+}
+
+func TestGDStore_MoveTreeToRoot(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutAll(map[string][]byte{"a": []byte("1"), "a/x": []byte("2"), "a/y/z": []byte("3")})
+	if err := store.MoveTree("a", ""); err != ErrInvalidTreeDestination {
+		t.Errorf("[%s] Expected ErrInvalidTreeDestination, got %v instead", t.Name(), err)
+	}
+	if err := store.CopyTree("a", ""); err != ErrInvalidTreeDestination {
+		t.Errorf("[%s] Expected ErrInvalidTreeDestination, got %v instead", t.Name(), err)
+	}
+	checkValueForKey(t, store, "a/x", []byte("2"))
+	checkKeyNotExists(t, store, "/x")
+	if store.Count() != 3 {
+		t.Errorf("[%s] Expected the store to still have 3 entries, got %d instead", t.Name(), store.Count())
+	}
+	store.Close()
+}
@@ -0,0 +1,223 @@
+package gdstore
+
+import (
+	"time"
+)
+
+// slidingRenewalDivisor determines how often the renewal of a key with a sliding TTL is persisted.
+//
+// Renewals are always applied in-memory, but they're only persisted once the expiration has been extended by
+// at least 1/slidingRenewalDivisor of the TTL since the last persisted expiration. This prevents a key that's
+// read thousands of times per second from filling the file with renewals, at the cost of the key potentially
+// expiring up to TTL/slidingRenewalDivisor earlier if the store is re-created.
+const slidingRenewalDivisor = 4
+
+// PutWithTTL creates an entry or updates the value of an existing key, and makes it expire after the given ttl.
+//
+// The absolute expiration time is persisted alongside the entry, which means that expired entries
+// are not loaded when the store is re-created.
+func (store *GDStore) PutWithTTL(key string, value []byte, ttl time.Duration) error {
+	return store.putWithExpiration(key, value, ttl, 0)
+}
+
+// PutWithSlidingTTL does the same thing as PutWithTTL, but the expiration is extended by ttl every time
+// the key is read with Get
+func (store *GDStore) PutWithSlidingTTL(key string, value []byte, ttl time.Duration) error {
+	return store.putWithExpiration(key, value, ttl, ttl)
+}
+
+func (store *GDStore) putWithExpiration(key string, value []byte, ttl, slidingTTL time.Duration) error {
+	defer store.runQueuedCallbacks()
+	unlock := store.lockKeys(key)
+	defer unlock()
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	store.set(key, value, 0)
+	store.setExpiration(key, time.Now().Add(ttl).UnixNano(), slidingTTL)
+	if err := store.appendEntryToFile(store.withExpiration(newEntry(ActionPut, key, value))); err != nil {
+		return err
+	}
+	return store.evict()
+}
+
+// Expire makes an existing key expire after the given ttl.
+// If the key had a sliding TTL, its expiration is no longer extended when it is read.
+//
+// Returns ErrKeyNotFound if the key doesn't exist.
+func (store *GDStore) Expire(key string, ttl time.Duration) error {
+	unlock := store.lockKeys(key)
+	defer unlock()
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	if !store.exists(key) {
+		return ErrKeyNotFound
+	}
+	store.setExpiration(key, time.Now().Add(ttl).UnixNano(), 0)
+	return store.appendEntryToFile(store.withExpiration(newEntry(ActionExpire, key, nil)))
+}
+
+// TTL returns the time left before a key expires, as well as a bool that indicates whether the key
+// exists and has an expiration
+func (store *GDStore) TTL(key string) (time.Duration, bool) {
+	store.mux.RLock()
+	defer store.mux.RUnlock()
+	now := time.Now().UnixNano()
+	expiry, hasExpiry := store.expiries[key]
+	if !hasExpiry || store.isExpired(key, now) {
+		return 0, false
+	}
+	return time.Duration(expiry - now), true
+}
+
+// OnExpire sets a function that is called with the key and the value of every expired entry deleted from memory,
+// whether it was deleted by the janitor or by a read.
+//
+// The function is called after the store has been unlocked, which means that it's safe to use the store from it.
+func (store *GDStore) OnExpire(callback func(key string, value []byte)) {
+	store.mux.Lock()
+	store.expireCallback = callback
+	store.mux.Unlock()
+}
+
+// WithJanitor starts a goroutine that deletes expired entries from memory at the given interval.
+//
+// Expired entries are never returned, even without a janitor, but they keep using memory until the janitor
+// (or a consolidation) gets rid of them. Calling WithJanitor again replaces the previous janitor.
+// The janitor can be stopped with StopJanitor.
+func (store *GDStore) WithJanitor(interval time.Duration) *GDStore {
+	store.StopJanitor()
+	stop := make(chan struct{})
+	store.mux.Lock()
+	store.janitorStop = stop
+	store.mux.Unlock()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				store.deleteExpiredEntries()
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return store
+}
+
+// StopJanitor stops the janitor started by WithJanitor. Does nothing if there is no janitor running.
+func (store *GDStore) StopJanitor() {
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	if store.janitorStop != nil {
+		close(store.janitorStop)
+		store.janitorStop = nil
+	}
+}
+
+// deleteExpiredEntries removes all expired entries from memory.
+//
+// No DEL is persisted, because the expiration time of each entry is already in the file.
+func (store *GDStore) deleteExpiredEntries() {
+	defer store.runQueuedCallbacks()
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	store.expireEntries(store.removeExpiredEntries(time.Now().UnixNano()))
+}
+
+// deleteExpiredEntry removes a key from memory if it has expired
+func (store *GDStore) deleteExpiredEntry(key string) {
+	defer store.runQueuedCallbacks()
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	if value, exists := store.data[key]; exists && store.isExpired(key, time.Now().UnixNano()) {
+		store.remove(key)
+		store.expireEntries(map[string][]byte{key: value})
+	}
+}
+
+// expireEntries updates the stats and queues the OnExpire callback for entries that have been removed
+// because they expired. Must be called while holding store.mux
+func (store *GDStore) expireEntries(expiredEntries map[string][]byte) {
+	for key, value := range expiredEntries {
+		store.stats.Expirations++
+		store.queueCallback(store.expireCallback, key, value)
+	}
+}
+
+// removeExpiredEntries removes all entries that have expired at the given time from memory and returns them.
+// Must be called while holding store.mux
+func (store *GDStore) removeExpiredEntries(now int64) map[string][]byte {
+	expiredEntries := make(map[string][]byte)
+	for key := range store.expiries {
+		if store.isExpired(key, now) {
+			expiredEntries[key] = store.data[key]
+			store.remove(key)
+		}
+	}
+	return expiredEntries
+}
+
+// renew extends the expiration of a key that has a sliding TTL, and persists the new expiration if
+// the last persisted expiration is lagging behind by too much
+func (store *GDStore) renew(key string) {
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	now := time.Now().UnixNano()
+	slidingTTL, sliding := store.slidingTTLs[key]
+	if !sliding || store.isExpired(key, now) {
+		return
+	}
+	store.expiries[key] = now + int64(slidingTTL)
+	if store.expiries[key]-store.persistedExpiries[key] < int64(slidingTTL/slidingRenewalDivisor) {
+		return
+	}
+	store.persistedExpiries[key] = store.expiries[key]
+	// If persisting the renewal fails, the worst case scenario is that the key expires earlier when the store is
+	// re-created, so there's no point in failing the read
+	_ = store.appendEntryToFile(store.withExpiration(newEntry(ActionExpire, key, nil)))
+}
+
+// setExpiration sets the expiration of a key. An expiry of 0 removes the expiration, and a slidingTTL of 0
+// means that the expiration isn't extended on read. Must be called while holding store.mux
+func (store *GDStore) setExpiration(key string, expiry int64, slidingTTL time.Duration) {
+	if expiry == 0 {
+		delete(store.expiries, key)
+	} else {
+		store.expiries[key] = expiry
+	}
+	if expiry == 0 || slidingTTL == 0 {
+		delete(store.slidingTTLs, key)
+		delete(store.persistedExpiries, key)
+	} else {
+		store.slidingTTLs[key] = slidingTTL
+		store.persistedExpiries[key] = expiry
+	}
+}
+
+// withExpiration sets the expiration of an entry to the current expiration of its key.
+// Must be called while holding store.mux
+func (store *GDStore) withExpiration(entry *Entry) *Entry {
+	entry.Expiry = store.expiries[entry.Key]
+	entry.SlidingTTL = store.slidingTTLs[entry.Key]
+	return entry
+}
+
+// isExpired returns whether a key has an expiration that has already passed.
+// Must be called while holding store.mux
+func (store *GDStore) isExpired(key string, now int64) bool {
+	expiry, hasExpiry := store.expiries[key]
+	return hasExpiry && expiry <= now
+}
+
+// numberOfExpiredEntries returns the number of entries that have expired, but that haven't been deleted yet.
+// Must be called while holding store.mux
+func (store *GDStore) numberOfExpiredEntries(now int64) int {
+	count := 0
+	for _, expiry := range store.expiries {
+		if expiry <= now {
+			count++
+		}
+	}
+	return count
+}
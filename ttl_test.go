@@ -0,0 +1,147 @@
+package gdstore
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGDStore_PutWithTTL(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutWithTTL("short", []byte("value"), 50*time.Millisecond)
+	_ = store.PutWithTTL("long", []byte("value"), time.Hour)
+	checkValueForKey(t, store, "short", []byte("value"))
+	if ttl, ok := store.TTL("long"); !ok || ttl <= 59*time.Minute {
+		t.Errorf("[%s] Expected key 'long' to have a TTL of about an hour, got %s instead", t.Name(), ttl)
+	}
+	time.Sleep(60 * time.Millisecond)
+	checkKeyNotExists(t, store, "short")
+	if store.Count() != 1 {
+		t.Errorf("[%s] Expected to have 1 entry, but got %d instead", t.Name(), store.Count())
+	}
+	if keys := store.Keys(); len(keys) != 1 || keys[0] != "long" {
+		t.Errorf("[%s] Expected only key 'long' to be returned, got %v instead", t.Name(), keys)
+	}
+	if values := store.Values(); len(values) != 1 {
+		t.Errorf("[%s] Expected 1 value, got %d instead", t.Name(), len(values))
+	}
+	store.Close()
+
+	// Make sure the expiration was persisted
+	store = New(TestStoreFile)
+	checkKeyNotExists(t, store, "short")
+	if _, ok := store.TTL("long"); !ok {
+		t.Errorf("[%s] Expected key 'long' to still have a TTL after reloading the store", t.Name())
+	}
+	if fileContent := getStoreFileContent(store); len(fileContent) == 0 || store.Count() != 1 {
+		t.Errorf("[%s] Expected the consolidated file to only contain key 'long'", t.Name())
+	}
+	store.Close()
+}
+
+func TestGDStore_Expire(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	if err := store.Expire("key", time.Hour); err != ErrKeyNotFound {
+		t.Errorf("[%s] Expected ErrKeyNotFound, got %v instead", t.Name(), err)
+	}
+	_ = store.Put("key", []byte("value"))
+	if _, ok := store.TTL("key"); ok {
+		t.Errorf("[%s] Expected key 'key' to have no TTL", t.Name())
+	}
+	_ = store.Expire("key", 50*time.Millisecond)
+	store.Close()
+	store = New(TestStoreFile)
+	checkValueForKey(t, store, "key", []byte("value"))
+	time.Sleep(60 * time.Millisecond)
+	checkKeyNotExists(t, store, "key")
+	store.Close()
+	store = New(TestStoreFile)
+	checkKeyNotExists(t, store, "key")
+	store.Close()
+}
+
+func TestGDStore_PutRemovesTTL(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutWithTTL("key", []byte("value"), time.Hour)
+	_ = store.Put("key", []byte("value"))
+	if _, ok := store.TTL("key"); ok {
+		t.Errorf("[%s] Expected Put to remove the TTL of key 'key'", t.Name())
+	}
+	store.Close()
+}
+
+func TestGDStore_WithJanitor(t *testing.T) {
+	store := New(TestStoreFile).WithJanitor(10 * time.Millisecond)
+	defer deleteTestStoreFile()
+	defer store.StopJanitor()
+	_ = store.PutWithTTL("key", []byte("value"), 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	store.mux.RLock()
+	_, exists := store.data["key"]
+	store.mux.RUnlock()
+	if exists {
+		t.Errorf("[%s] Expected the janitor to have deleted key 'key'", t.Name())
+	}
+	store.Close()
+}
+
+func TestGDStore_PutWithSlidingTTL(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutWithSlidingTTL("key", []byte("value"), 100*time.Millisecond)
+	for i := 0; i < 5; i++ {
+		time.Sleep(40 * time.Millisecond)
+		checkValueForKey(t, store, "key", []byte("value"))
+	}
+	time.Sleep(110 * time.Millisecond)
+	checkKeyNotExists(t, store, "key")
+	store.Close()
+}
+
+func TestGDStore_PutWithSlidingTTLCoalescesRenewals(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutWithSlidingTTL("key", []byte("value"), time.Hour)
+	for i := 0; i < 1000; i++ {
+		store.Get("key")
+	}
+	// The renewals are all within a fraction of the TTL, so none of them should've been persisted
+	if numberOfLines := len(strings.Split(getStoreFileContent(store), "\n")); numberOfLines != 1 {
+		t.Errorf("[%s] Store file should've had 1 line, but had %d instead", t.Name(), numberOfLines)
+	}
+	store.Close()
+
+	// Make sure the sliding TTL was persisted
+	store = New(TestStoreFile)
+	if _, ok := store.slidingTTLs["key"]; !ok {
+		t.Errorf("[%s] Expected key 'key' to still have a sliding TTL after reloading the store", t.Name())
+	}
+	store.Close()
+}
+
+func TestGDStore_OnExpire(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	expired := make(map[string]string)
+	var mutex sync.Mutex
+	store.OnExpire(func(key string, value []byte) {
+		mutex.Lock()
+		expired[key] = string(value)
+		mutex.Unlock()
+	})
+	_ = store.PutWithTTL("read", []byte("1"), 10*time.Millisecond)
+	_ = store.PutWithTTL("janitor", []byte("2"), 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	checkKeyNotExists(t, store, "read")
+	store.deleteExpiredEntries()
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(expired) != 2 || expired["read"] != "1" || expired["janitor"] != "2" {
+		t.Errorf("[%s] Expected both keys to have been passed to the OnExpire callback, got %v instead", t.Name(), expired)
+	}
+	store.Close()
+}
@@ -0,0 +1,156 @@
+package gdstore
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+// Codec converts values of type T to and from bytes
+type Codec[T any] interface {
+	Encode(value T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// DecodeError is returned when the value of a key could not be decoded
+type DecodeError struct {
+	Key string
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode value of key %s: %s", e.Key, e.Err.Error())
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Typed is a wrapper around a GDStore that encodes and decodes values of type T using a Codec
+type Typed[T any] struct {
+	store *GDStore
+	codec Codec[T]
+}
+
+// NewTyped creates a new Typed wrapper around a store
+func NewTyped[T any](store *GDStore, codec Codec[T]) *Typed[T] {
+	return &Typed[T]{store: store, codec: codec}
+}
+
+// Store returns the underlying store
+func (typed *Typed[T]) Store() *GDStore {
+	return typed.store
+}
+
+// Get returns the decoded value of a key as well as a bool that indicates whether an entry exists for that key.
+//
+// If the value cannot be decoded, a *DecodeError is returned.
+func (typed *Typed[T]) Get(key string) (value T, ok bool, err error) {
+	return getDecoded(typed.store, key, typed.codec.Decode)
+}
+
+// Put encodes a value and creates an entry or updates the value of an existing key
+func (typed *Typed[T]) Put(key string, value T) error {
+	return putEncoded(typed.store, key, value, typed.codec.Encode)
+}
+
+// getDecoded returns the value of a key decoded with the decode function of a Codec.
+// If the value cannot be decoded, a *DecodeError is returned.
+func getDecoded[T any](store *GDStore, key string, decode func(data []byte) (T, error)) (value T, ok bool, err error) {
+	var data []byte
+	if data, ok = store.Get(key); !ok {
+		return
+	}
+	if value, err = decode(data); err != nil {
+		err = &DecodeError{Key: key, Err: err}
+	}
+	return
+}
+
+// getDecodedOr returns the value of a key decoded with the decode function of a Codec, or defaultValue if the key
+// doesn't exist or its value cannot be decoded
+func getDecodedOr[T any](store *GDStore, key string, decode func(data []byte) (T, error), defaultValue T) T {
+	value, ok, err := getDecoded(store, key, decode)
+	if !ok || err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// putEncoded encodes a value with the encode function of a Codec and creates an entry or updates the value of an
+// existing key
+func putEncoded[T any](store *GDStore, key string, value T, encode func(value T) ([]byte, error)) error {
+	data, err := encode(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode value of key %s: %w", key, err)
+	}
+	return store.Put(key, data)
+}
+
+// JSONCodec encodes values as JSON
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) Encode(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (JSONCodec[T]) Decode(data []byte) (value T, err error) {
+	err = json.Unmarshal(data, &value)
+	return
+}
+
+// GobCodec encodes values using encoding/gob
+type GobCodec[T any] struct{}
+
+func (GobCodec[T]) Encode(value T) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(value); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (GobCodec[T]) Decode(data []byte) (value T, err error) {
+	err = gob.NewDecoder(bytes.NewReader(data)).Decode(&value)
+	return
+}
+
+// BinaryCodec encodes values using their implementation of encoding.BinaryMarshaler and
+// encoding.BinaryUnmarshaler. Use NewBinaryCodec to create one.
+type BinaryCodec[T any, PT interface {
+	*T
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}] struct{}
+
+// NewBinaryCodec creates a BinaryCodec for values of type T, where *T implements both encoding.BinaryMarshaler
+// and encoding.BinaryUnmarshaler (e.g. NewBinaryCodec[time.Time]())
+func NewBinaryCodec[T any, PT interface {
+	*T
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}]() BinaryCodec[T, PT] {
+	return BinaryCodec[T, PT]{}
+}
+
+func (BinaryCodec[T, PT]) Encode(value T) ([]byte, error) {
+	return PT(&value).MarshalBinary()
+}
+
+func (BinaryCodec[T, PT]) Decode(data []byte) (value T, err error) {
+	err = PT(&value).UnmarshalBinary(data)
+	return
+}
+
+// StringCodec stores strings as is
+type StringCodec struct{}
+
+func (StringCodec) Encode(value string) ([]byte, error) {
+	return []byte(value), nil
+}
+
+func (StringCodec) Decode(data []byte) (string, error) {
+	return string(data), nil
+}
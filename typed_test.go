@@ -0,0 +1,71 @@
+package gdstore
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type testUser struct {
+	Name string
+	Age  int
+}
+
+func TestTyped_JSONCodec(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	users := NewTyped[testUser](store, JSONCodec[testUser]{})
+	if err := users.Put("john", testUser{Name: "John", Age: 42}); err != nil {
+		t.Fatalf("[%s] Unexpected error: %s", t.Name(), err.Error())
+	}
+	checkValueForKey(t, store, "john", []byte(`{"Name":"John","Age":42}`))
+	user, ok, err := users.Get("john")
+	if !ok || err != nil || user.Name != "John" || user.Age != 42 {
+		t.Errorf("[%s] Expected John (42), got %+v, %v and %v instead", t.Name(), user, ok, err)
+	}
+	if _, ok, err = users.Get("jane"); ok || err != nil {
+		t.Errorf("[%s] Expected jane to not exist", t.Name())
+	}
+	_ = store.Put("invalid", []byte("{"))
+	_, ok, err = users.Get("invalid")
+	var decodeError *DecodeError
+	if !ok || !errors.As(err, &decodeError) || decodeError.Key != "invalid" {
+		t.Errorf("[%s] Expected a DecodeError for key invalid, got %v instead", t.Name(), err)
+	}
+	store.Close()
+}
+
+func TestTyped_GobCodec(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	users := NewTyped[testUser](store, GobCodec[testUser]{})
+	_ = users.Put("john", testUser{Name: "John", Age: 42})
+	if user, ok, err := users.Get("john"); !ok || err != nil || user.Name != "John" || user.Age != 42 {
+		t.Errorf("[%s] Expected John (42), got %+v, %v and %v instead", t.Name(), user, ok, err)
+	}
+	store.Close()
+}
+
+func TestTyped_BinaryCodec(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	timestamps := NewTyped[time.Time](store, NewBinaryCodec[time.Time]())
+	now := time.Now()
+	_ = timestamps.Put("now", now)
+	if timestamp, ok, err := timestamps.Get("now"); !ok || err != nil || !timestamp.Equal(now) {
+		t.Errorf("[%s] Expected %s, got %s, %v and %v instead", t.Name(), now, timestamp, ok, err)
+	}
+	store.Close()
+}
+
+func TestTyped_StringCodec(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	names := NewTyped[string](store, StringCodec{})
+	_ = names.Put("1", "john")
+	checkValueForKey(t, store, "1", []byte("john"))
+	if name, ok, err := names.Get("1"); !ok || err != nil || name != "john" {
+		t.Errorf("[%s] Expected john, got %s, %v and %v instead", t.Name(), name, ok, err)
+	}
+	store.Close()
+}
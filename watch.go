@@ -0,0 +1,260 @@
+package gdstore
+
+import (
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultWatchBufferSize is the default size of the buffer of the channels returned by Watch
+	DefaultWatchBufferSize = 64
+)
+
+// OverflowPolicy defines what happens to the events of a watcher whose channel is full
+type OverflowPolicy string
+
+var (
+	// OverflowPolicyBlock waits until there's room in the channel. No event is lost, but the goroutine that
+	// delivers the events is blocked until the watcher catches up.
+	//
+	// This is the default policy.
+	OverflowPolicyBlock OverflowPolicy = "block"
+
+	// OverflowPolicyDrop discards the events that don't fit in the channel
+	OverflowPolicyDrop OverflowPolicy = "drop"
+
+	// OverflowPolicyCoalesce keeps the events that don't fit in the channel aside, and combines the events that
+	// concern the same key into a single event with the OldValue of the first event and the Action and NewValue of
+	// the last event. Writers are never blocked, and the watcher always ends up with the latest value of every key.
+	OverflowPolicyCoalesce OverflowPolicy = "coalesce"
+)
+
+// Event is a change made to an entry of the store
+type Event struct {
+	// Action is ActionPut if the key was created or updated, and ActionDelete if it was removed
+	Action Action
+
+	Key string
+
+	// OldValue is the value of the key before the change, or nil if the key didn't exist
+	OldValue []byte
+
+	// NewValue is the value of the key after the change, or nil if the key was removed
+	NewValue []byte
+}
+
+// WithWatchBufferSize sets the size of the buffer of the channels returned by subsequent calls to Watch
+//
+// The default value for the buffer size is DefaultWatchBufferSize
+func (store *GDStore) WithWatchBufferSize(size int) *GDStore {
+	store.mux.Lock()
+	store.watchBufferSize = size
+	store.mux.Unlock()
+	return store
+}
+
+// WithWatchOverflowPolicy sets what happens to the events of the channels returned by subsequent calls to Watch
+// when they are full
+//
+// The default value for the policy is OverflowPolicyBlock
+func (store *GDStore) WithWatchOverflowPolicy(policy OverflowPolicy) *GDStore {
+	store.mux.Lock()
+	store.watchOverflowPolicy = policy
+	store.mux.Unlock()
+	return store
+}
+
+// Watch returns a channel on which an Event is sent every time an entry whose key starts with the prefix passed as
+// parameter is created, updated or removed, including when a key is removed because it expired or was evicted.
+// Changes made to entries in buckets are not included.
+//
+// Events are sent in the order in which the changes were made, once they have been persisted. What happens when
+// the channel is full is defined by WithWatchOverflowPolicy.
+//
+// The function returned must be called once the channel is no longer needed, at which point the channel is closed.
+func (store *GDStore) Watch(prefix string) (<-chan Event, func()) {
+	store.mux.Lock()
+	w := newWatcher(prefix, store.watchBufferSize, store.watchOverflowPolicy)
+	store.watchers = append(store.watchers, w)
+	store.mux.Unlock()
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			store.mux.Lock()
+			for i, other := range store.watchers {
+				if other == w {
+					store.watchers = append(store.watchers[:i], store.watchers[i+1:]...)
+					break
+				}
+			}
+			store.mux.Unlock()
+			w.close()
+		})
+	}
+	return w.events, cancel
+}
+
+// OnChange sets the function called every time an entry of the store is created, updated or removed.
+// See Watch for more details.
+//
+// The function is called in the order in which the changes were made, once they have been persisted, and after the
+// store has been unlocked, so it's safe to use the store from within the callback. It should return quickly, as
+// other changes aren't delivered until it does.
+func (store *GDStore) OnChange(callback func(event Event)) {
+	store.mux.Lock()
+	store.changeCallback = callback
+	store.mux.Unlock()
+}
+
+// recordChange queues an event for the change of an entry if anything is watching the store.
+// Must be called while holding store.mux
+func (store *GDStore) recordChange(action Action, key string, oldValue, newValue []byte) {
+	if len(store.watchers) == 0 && store.changeCallback == nil {
+		return
+	}
+	store.pendingEvents = append(store.pendingEvents, Event{Action: action, Key: key, OldValue: oldValue, NewValue: newValue})
+}
+
+// dispatchEvents delivers the events queued by recordChange. Must NOT be called while holding store.mux
+//
+// Only one goroutine delivers events at a time, which guarantees that they are delivered in order. If another
+// goroutine is already delivering events, it takes care of delivering the events queued in the meantime.
+func (store *GDStore) dispatchEvents() {
+	for {
+		if !store.dispatchMux.TryLock() {
+			return
+		}
+		// Because events are queued while holding store.mux, taking them while holding store.mux ensures that
+		// they are only delivered once they've been persisted
+		store.mux.Lock()
+		events := store.pendingEvents
+		store.pendingEvents = nil
+		watchers := append([]*watcher(nil), store.watchers...)
+		callback := store.changeCallback
+		store.mux.Unlock()
+		for _, event := range events {
+			if callback != nil {
+				callback(event)
+			}
+			for _, w := range watchers {
+				if strings.HasPrefix(event.Key, w.prefix) {
+					w.send(event)
+				}
+			}
+		}
+		store.dispatchMux.Unlock()
+		// Events may have been queued by goroutines that couldn't deliver them because the lock was held
+		store.mux.RLock()
+		hasPendingEvents := len(store.pendingEvents) > 0
+		store.mux.RUnlock()
+		if !hasPendingEvents {
+			return
+		}
+	}
+}
+
+// watcher is a channel returned by Watch
+type watcher struct {
+	prefix string
+	policy OverflowPolicy
+	events chan Event
+	done   chan struct{}
+
+	// mux protects closed and, with OverflowPolicyCoalesce, pending and pendingByKey
+	mux    sync.Mutex
+	closed bool
+
+	// pending contains the events that didn't fit in the channel. Only used with OverflowPolicyCoalesce.
+	pending      []*Event
+	pendingByKey map[string]*Event
+	notify       chan struct{}
+}
+
+func newWatcher(prefix string, bufferSize int, policy OverflowPolicy) *watcher {
+	w := &watcher{
+		prefix: prefix,
+		policy: policy,
+		events: make(chan Event, bufferSize),
+		done:   make(chan struct{}),
+	}
+	if policy == OverflowPolicyCoalesce {
+		w.pendingByKey = make(map[string]*Event)
+		w.notify = make(chan struct{}, 1)
+		go w.pump()
+	}
+	return w
+}
+
+// send sends an event to the watcher according to its overflow policy
+func (w *watcher) send(event Event) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if w.closed {
+		return
+	}
+	switch w.policy {
+	case OverflowPolicyDrop:
+		select {
+		case w.events <- event:
+		default:
+		}
+	case OverflowPolicyCoalesce:
+		if pendingEvent, exists := w.pendingByKey[event.Key]; exists {
+			pendingEvent.Action, pendingEvent.NewValue = event.Action, event.NewValue
+		} else {
+			w.pending = append(w.pending, &event)
+			w.pendingByKey[event.Key] = &event
+		}
+		select {
+		case w.notify <- struct{}{}:
+		default:
+		}
+	default:
+		select {
+		case w.events <- event:
+		case <-w.done:
+		}
+	}
+}
+
+// pump moves the pending events to the channel as soon as there's room for them.
+// Only used with OverflowPolicyCoalesce.
+func (w *watcher) pump() {
+	defer close(w.events)
+	for {
+		select {
+		case <-w.notify:
+		case <-w.done:
+			return
+		}
+		for {
+			w.mux.Lock()
+			if len(w.pending) == 0 {
+				w.mux.Unlock()
+				break
+			}
+			event := *w.pending[0]
+			w.pending[0] = nil
+			w.pending = w.pending[1:]
+			delete(w.pendingByKey, event.Key)
+			w.mux.Unlock()
+			select {
+			case w.events <- event:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+// close stops the watcher and closes its channel
+func (w *watcher) close() {
+	// done must be closed before locking w.mux, since send may be blocked while holding it
+	close(w.done)
+	w.mux.Lock()
+	w.closed = true
+	if w.policy != OverflowPolicyCoalesce {
+		close(w.events)
+	}
+	w.mux.Unlock()
+}
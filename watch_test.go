@@ -0,0 +1,153 @@
+package gdstore
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGDStore_Watch(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	events, cancel := store.Watch("user:")
+	_ = store.Put("user:1", []byte("john"))
+	_ = store.Put("other", []byte("ignored"))
+	_ = store.Put("user:1", []byte("jane"))
+	_ = store.Delete("user:1")
+	_ = store.PutAll(map[string][]byte{"user:2": []byte("bob")})
+	expectedEvents := []Event{
+		{Action: ActionPut, Key: "user:1", NewValue: []byte("john")},
+		{Action: ActionPut, Key: "user:1", OldValue: []byte("john"), NewValue: []byte("jane")},
+		{Action: ActionDelete, Key: "user:1", OldValue: []byte("jane")},
+		{Action: ActionPut, Key: "user:2", NewValue: []byte("bob")},
+	}
+	for _, expectedEvent := range expectedEvents {
+		select {
+		case event := <-events:
+			if event.Action != expectedEvent.Action || event.Key != expectedEvent.Key || string(event.OldValue) != string(expectedEvent.OldValue) || string(event.NewValue) != string(expectedEvent.NewValue) {
+				t.Errorf("[%s] Expected %+v, got %+v instead", t.Name(), expectedEvent, event)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("[%s] Expected %+v, got nothing instead", t.Name(), expectedEvent)
+		}
+	}
+	cancel()
+	cancel()
+	_ = store.Put("user:3", []byte("alice"))
+	if _, ok := <-events; ok {
+		t.Errorf("[%s] Expected the channel to be closed", t.Name())
+	}
+	store.Close()
+}
+
+func TestGDStore_WatchExpiration(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	_ = store.PutWithTTL("key", []byte("value"), 10*time.Millisecond)
+	events, cancel := store.Watch("")
+	defer cancel()
+	time.Sleep(20 * time.Millisecond)
+	checkKeyNotExists(t, store, "key")
+	if event := <-events; event.Action != ActionDelete || event.Key != "key" || string(event.OldValue) != "value" {
+		t.Errorf("[%s] Expected a DEL event for key, got %+v instead", t.Name(), event)
+	}
+	store.Close()
+}
+
+func TestGDStore_WatchWithDropOverflowPolicy(t *testing.T) {
+	store := New(TestStoreFile).WithWatchBufferSize(2).WithWatchOverflowPolicy(OverflowPolicyDrop)
+	defer deleteTestStoreFile()
+	events, cancel := store.Watch("")
+	for i := 0; i < 5; i++ {
+		_ = store.Put(strconv.Itoa(i), []byte("value"))
+	}
+	cancel()
+	var keys []string
+	for event := range events {
+		keys = append(keys, event.Key)
+	}
+	if len(keys) != 2 || keys[0] != "0" || keys[1] != "1" {
+		t.Errorf("[%s] Expected only the events of keys 0 and 1, got %v instead", t.Name(), keys)
+	}
+	store.Close()
+}
+
+func TestGDStore_WatchWithCoalesceOverflowPolicy(t *testing.T) {
+	store := New(TestStoreFile).WithWatchBufferSize(1).WithWatchOverflowPolicy(OverflowPolicyCoalesce)
+	defer deleteTestStoreFile()
+	events, cancel := store.Watch("")
+	defer cancel()
+	for i := 0; i < 100; i++ {
+		_ = store.Put("a", []byte(strconv.Itoa(i)))
+		_ = store.Put("b", []byte(strconv.Itoa(i)))
+	}
+	_ = store.Delete("b")
+	latestEvents := make(map[string]Event)
+	numberOfEvents := 0
+	timeout := time.After(time.Second)
+	for latestEvents["a"].NewValue == nil || string(latestEvents["a"].NewValue) != "99" || latestEvents["b"].Action != ActionDelete {
+		select {
+		case event := <-events:
+			latestEvents[event.Key] = event
+			numberOfEvents++
+		case <-timeout:
+			t.Fatalf("[%s] Expected the latest events to be delivered, got %+v instead", t.Name(), latestEvents)
+		}
+	}
+	if numberOfEvents > 201 {
+		t.Errorf("[%s] Expected at most 201 events, got %d instead", t.Name(), numberOfEvents)
+	}
+	store.Close()
+}
+
+func TestGDStore_WatchWithBlockOverflowPolicy(t *testing.T) {
+	store := New(TestStoreFile).WithWatchBufferSize(1)
+	defer deleteTestStoreFile()
+	events, cancel := store.Watch("")
+	defer cancel()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_ = store.Put(strconv.Itoa(i), []byte("value"))
+		}(i)
+	}
+	seen := make(map[string]bool)
+	for len(seen) < 10 {
+		select {
+		case event := <-events:
+			seen[event.Key] = true
+		case <-time.After(time.Second):
+			t.Fatalf("[%s] Expected 10 events, got %d instead", t.Name(), len(seen))
+		}
+	}
+	wg.Wait()
+	store.Close()
+}
+
+func TestGDStore_OnChange(t *testing.T) {
+	store := New(TestStoreFile)
+	defer deleteTestStoreFile()
+	var events []Event
+	store.OnChange(func(event Event) {
+		events = append(events, event)
+		// Using the store from within the callback must not cause a deadlock
+		if event.Key == "counter" {
+			_ = store.Put("copy", event.NewValue)
+		}
+	})
+	_, _ = store.Increment("counter", 1)
+	_ = store.Rename("copy", "renamed", false)
+	expectedKeys := []string{"counter", "copy", "copy", "renamed"}
+	if len(events) != len(expectedKeys) {
+		t.Fatalf("[%s] Expected %d events, got %+v instead", t.Name(), len(expectedKeys), events)
+	}
+	for i, expectedKey := range expectedKeys {
+		if events[i].Key != expectedKey {
+			t.Errorf("[%s] Expected event %d to be for key %s, got %+v instead", t.Name(), i, expectedKey, events[i])
+		}
+	}
+	store.Close()
+}